@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -8,6 +9,7 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/damion-14/cadence/backend/internal/cache"
 	"github.com/damion-14/cadence/backend/internal/config"
@@ -37,29 +39,44 @@ func run() error {
 	defer db.Close()
 	fmt.Println("Connected to PostgreSQL")
 
-	redisClient, err := cache.NewRedisClient(cfg.Redis)
+	cacheClient, err := cache.New(cfg.Cache, cfg.Redis)
 	if err != nil {
-		return fmt.Errorf("failed to connect to redis: %w", err)
+		return fmt.Errorf("failed to connect to cache: %w", err)
 	}
-	defer redisClient.Close()
-	fmt.Println("Connected to Redis")
+	defer cacheClient.Close()
+	fmt.Printf("Using %s cache\n", cfg.Cache.Driver)
 
-	cacheClient := cache.NewCache(redisClient)
-
-	workoutService := services.NewWorkoutService(db, cacheClient)
+	authService := services.NewAuthService(db, cacheClient, cfg.JWT)
+	workoutService := services.NewWorkoutService(db, cacheClient, cfg.Workout)
 	statsService := services.NewStatsService(db, cacheClient)
+	routineService := services.NewRoutineService(db)
+	catalogService := services.NewCatalogService(db)
+	userService := services.NewUserService(db, cacheClient)
+	exportService := services.NewExportService(db, cfg.Export)
+	importService := services.NewImportService(db, cacheClient)
+	searchService := services.NewSearchService(db)
 
 	deps := &router.Dependencies{
 		DB:              db,
-		Redis:           redisClient,
-		Cache:           cacheClient,
+		Denylist:        authService,
 		Config:          cfg,
-		AuthHandler:     handlers.NewAuthHandler(db, cfg.JWT),
-		WorkoutHandler:  handlers.NewWorkoutHandler(workoutService),
-		ExerciseHandler: handlers.NewExerciseHandler(workoutService),
-		StatsHandler:    handlers.NewStatsHandler(statsService),
+		AuthHandler:     handlers.NewAuthHandler(db, authService),
+		WorkoutHandler:  handlers.NewWorkoutHandler(workoutService, userService),
+		ExerciseHandler: handlers.NewExerciseHandler(workoutService, userService),
+		SetHandler:      handlers.NewSetHandler(workoutService, userService),
+		StatsHandler:    handlers.NewStatsHandler(statsService, userService),
+		RoutineHandler:  handlers.NewRoutineHandler(routineService, userService),
+		CatalogHandler:  handlers.NewCatalogHandler(catalogService),
+		UserHandler:     handlers.NewUserHandler(userService),
+		ExportHandler:   handlers.NewExportHandler(exportService, userService),
+		ImportHandler:   handlers.NewImportHandler(importService, userService),
+		SearchHandler:   handlers.NewSearchHandler(searchService),
 	}
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	go workoutService.RunAutoAbandon(jobsCtx)
+
 	mux := router.NewRouter(deps)
 
 	handler := middleware.Recovery(
@@ -70,6 +87,8 @@ func run() error {
 		),
 	)
 
+	// Long-lived responses such as the active workout stream manage their
+	// own write deadlines through http.ResponseController.
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
 		Handler:      handler,
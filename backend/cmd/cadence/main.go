@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/config"
+	"github.com/damion-14/cadence/backend/internal/database"
+	"github.com/damion-14/cadence/backend/internal/database/migrations"
+)
+
+const usage = `Usage:
+  cadence migrate up           Apply all pending migrations
+  cadence migrate down [n]     Revert the last n migrations (default 1)
+  cadence migrate status       Show applied and pending migrations`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(args []string) error {
+	if len(args) < 2 || args[0] != "migrate" {
+		fmt.Println(usage)
+		return fmt.Errorf("invalid command")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Migrating is how a stale schema gets fixed, so never refuse to connect here.
+	cfg.Database.RequireCurrentSchema = false
+
+	db, err := database.NewPostgresPool(cfg.Database)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	switch args[1] {
+	case "up":
+		return migrateUp(ctx, migrator)
+	case "down":
+		steps := 1
+		if len(args) > 2 {
+			steps, err = strconv.Atoi(args[2])
+			if err != nil || steps <= 0 {
+				return fmt.Errorf("invalid step count %q", args[2])
+			}
+		}
+		return migrateDown(ctx, migrator, steps)
+	case "status":
+		return migrateStatus(ctx, migrator)
+	default:
+		fmt.Println(usage)
+		return fmt.Errorf("unknown migrate command %q", args[1])
+	}
+}
+
+func migrateUp(ctx context.Context, migrator *migrations.Migrator) error {
+	applied, err := migrator.Up(ctx)
+	for _, migration := range applied {
+		fmt.Printf("Applied %04d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("Schema is up to date")
+	}
+	return nil
+}
+
+func migrateDown(ctx context.Context, migrator *migrations.Migrator, steps int) error {
+	reverted, err := migrator.Down(ctx, steps)
+	for _, migration := range reverted {
+		fmt.Printf("Reverted %04d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(reverted) == 0 {
+		fmt.Println("No migrations to revert")
+	}
+	return nil
+}
+
+func migrateStatus(ctx context.Context, migrator *migrations.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		state := "pending"
+		if status.Applied {
+			state = "applied " + status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%04d_%-40s %s\n", status.Version, status.Name, state)
+	}
+	return nil
+}
@@ -1,14 +1,25 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 
 	"github.com/damion-14/cadence/backend/internal/middleware"
 )
 
-func Middleware(secret string) func(http.Handler) http.Handler {
+// Denylist reports whether an access token has been revoked before its
+// expiry.
+type Denylist interface {
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// Middleware rejects requests whose token is revoked. When the denylist
+// cannot be read it fails closed with 503 rather than letting a revoked
+// token through.
+func Middleware(secret string, denylist Denylist) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -30,20 +41,36 @@ func Middleware(secret string) func(http.Handler) http.Handler {
 				return
 			}
 
+			revoked, err := denylist.IsTokenRevoked(r.Context(), claims.ID)
+			if err != nil {
+				fmt.Printf("Failed to check token denylist: %v\n", err)
+				respondAuthError(w, r, http.StatusServiceUnavailable, "SERVICE_UNAVAILABLE", "Unable to verify token, please retry")
+				return
+			}
+			if revoked {
+				respondUnauthorized(w, r, "Token has been revoked")
+				return
+			}
+
 			ctx := middleware.SetUserID(r.Context(), claims.UserID)
+			ctx = middleware.SetTokenID(ctx, claims.ID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
 func respondUnauthorized(w http.ResponseWriter, r *http.Request, message string) {
+	respondAuthError(w, r, http.StatusUnauthorized, "UNAUTHORIZED", message)
+}
+
+func respondAuthError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
 	requestID := middleware.GetRequestID(r.Context())
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusUnauthorized)
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"error": map[string]string{
-			"code":       "UNAUTHORIZED",
+			"code":       code,
 			"message":    message,
 			"request_id": requestID,
 		},
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/config"
+)
+
+const (
+	DriverRedis  = "redis"
+	DriverMemory = "memory"
+)
+
+var (
+	// ErrMiss is returned by Get for keys that are absent or expired.
+	ErrMiss = errors.New("cache: key not found")
+
+	// ErrUnavailable is returned while the cache backend cannot be reached.
+	ErrUnavailable = errors.New("cache: backend unavailable")
+)
+
+// Cache holds derived data such as the active workout and stats, the access
+// token denylist and the rest timer, and carries workout events between API
+// instances. Callers treat any Get error as a miss and read from the
+// database instead.
+type Cache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePattern removes every key matching a glob pattern such as
+	// "prs:user:1:*".
+	DeletePattern(ctx context.Context, pattern string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	Publish(ctx context.Context, channel string, message interface{}) error
+	Subscribe(ctx context.Context, channel string) (*Subscription, error)
+	Close() error
+}
+
+// New builds the cache selected by cfg. The memory driver keeps everything
+// in this process, so it only suits a single API instance. With fallback
+// enabled an unreachable Redis does not fail startup; the cache reports
+// itself unavailable until Redis answers again.
+func New(cfg config.CacheConfig, redisCfg config.RedisConfig) (Cache, error) {
+	switch cfg.Driver {
+	case DriverMemory:
+		return NewMemoryCache(cfg.MemoryMaxEntries), nil
+
+	case DriverRedis:
+		if cfg.RedisFallback {
+			return NewFallbackCache(newRedisClient(redisCfg)), nil
+		}
+
+		client, err := NewRedisClient(redisCfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewRedisCache(client), nil
+	}
+
+	return nil, fmt.Errorf("unknown cache driver %q", cfg.Driver)
+}
+
+// stringValue renders a value the way Redis stores it.
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	}
+	return fmt.Sprint(value)
+}
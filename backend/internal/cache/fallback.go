@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fallbackCheckInterval is how often an unreachable Redis is pinged.
+const fallbackCheckInterval = 5 * time.Second
+
+// fallbackFlushPatterns match the cached copies of database state, the
+// token denylist included. Writes made while Redis was unreachable could not
+// update them, so they are dropped before Redis is used again.
+var fallbackFlushPatterns = []string{
+	"active_workout:*",
+	"rest_timer:*",
+	"prs:*",
+	"weekly:*",
+	"progress:*",
+	"prefs:*",
+	"revoked_token:*",
+}
+
+// FallbackCache uses Redis while it answers. Once a call fails it returns
+// ErrUnavailable without waiting on Redis, so reads go straight to the
+// database, until a background ping succeeds again.
+type FallbackCache struct {
+	redis     *RedisCache
+	available atomic.Bool
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+func NewFallbackCache(client *redis.Client) *FallbackCache {
+	c := &FallbackCache{
+		redis: NewRedisCache(client),
+		stop:  make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		fmt.Printf("Redis unavailable, reading from the database until it returns: %v\n", err)
+	} else {
+		c.available.Store(true)
+	}
+
+	go c.monitor()
+	return c
+}
+
+func (c *FallbackCache) Get(ctx context.Context, key string) (string, error) {
+	if !c.available.Load() {
+		return "", ErrUnavailable
+	}
+	value, err := c.redis.Get(ctx, key)
+	return value, c.check(err)
+}
+
+func (c *FallbackCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if !c.available.Load() {
+		return ErrUnavailable
+	}
+	return c.check(c.redis.Set(ctx, key, value, ttl))
+}
+
+func (c *FallbackCache) Delete(ctx context.Context, keys ...string) error {
+	if !c.available.Load() {
+		return ErrUnavailable
+	}
+	return c.check(c.redis.Delete(ctx, keys...))
+}
+
+func (c *FallbackCache) DeletePattern(ctx context.Context, pattern string) error {
+	if !c.available.Load() {
+		return ErrUnavailable
+	}
+	return c.check(c.redis.DeletePattern(ctx, pattern))
+}
+
+func (c *FallbackCache) Exists(ctx context.Context, key string) (bool, error) {
+	if !c.available.Load() {
+		return false, ErrUnavailable
+	}
+	exists, err := c.redis.Exists(ctx, key)
+	return exists, c.check(err)
+}
+
+func (c *FallbackCache) Publish(ctx context.Context, channel string, message interface{}) error {
+	if !c.available.Load() {
+		return ErrUnavailable
+	}
+	return c.check(c.redis.Publish(ctx, channel, message))
+}
+
+func (c *FallbackCache) Subscribe(ctx context.Context, channel string) (*Subscription, error) {
+	if !c.available.Load() {
+		return nil, ErrUnavailable
+	}
+	sub, err := c.redis.Subscribe(ctx, channel)
+	return sub, c.check(err)
+}
+
+func (c *FallbackCache) Close() error {
+	c.stopOnce.Do(func() { close(c.stop) })
+	return c.redis.Close()
+}
+
+// check marks Redis unavailable when err means it could not be reached.
+// Misses, cancelled requests and errors Redis itself replied with do not.
+func (c *FallbackCache) check(err error) error {
+	if err == nil || errors.Is(err, ErrMiss) || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	var replyErr redis.Error
+	if errors.As(err, &replyErr) {
+		return err
+	}
+
+	if c.available.CompareAndSwap(true, false) {
+		fmt.Printf("Redis unavailable, reading from the database until it returns: %v\n", err)
+	}
+	return err
+}
+
+func (c *FallbackCache) monitor() {
+	ticker := time.NewTicker(fallbackCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			if !c.available.Load() {
+				c.reconnect()
+			}
+		}
+	}
+}
+
+// reconnect switches back to Redis once it answers and stale entries are gone.
+func (c *FallbackCache) reconnect() {
+	ctx, cancel := context.WithTimeout(context.Background(), fallbackCheckInterval)
+	defer cancel()
+
+	if err := c.redis.client.Ping(ctx).Err(); err != nil {
+		return
+	}
+
+	for _, pattern := range fallbackFlushPatterns {
+		if err := c.redis.DeletePattern(ctx, pattern); err != nil {
+			fmt.Printf("Failed to flush stale cache entries: %v\n", err)
+			return
+		}
+	}
+
+	c.available.Store(true)
+	fmt.Println("Redis available again")
+}
@@ -0,0 +1,358 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// fakeRedis speaks just enough RESP for FallbackCache: PING, GET, SET, DEL,
+// EXISTS, SCAN and PUBLISH. Anything else gets an error reply, which the
+// client tolerates during its handshake. While down it drops every
+// connection, as an unreachable server would.
+type fakeRedis struct {
+	listener net.Listener
+
+	mu    sync.Mutex
+	data  map[string]string
+	down  bool
+	conns map[net.Conn]struct{}
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeRedis{listener: listener, data: map[string]string{}, conns: map[net.Conn]struct{}{}}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+
+	return f
+}
+
+func (f *fakeRedis) client() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:            f.listener.Addr().String(),
+		Protocol:        2,
+		DisableIdentity: true,
+		MaxRetries:      -1,
+		DialTimeout:     time.Second,
+	})
+}
+
+func (f *fakeRedis) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.down = down
+	if down {
+		for conn := range f.conns {
+			conn.Close()
+		}
+	}
+}
+
+func (f *fakeRedis) has(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	_, ok := f.data[key]
+	return ok
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	f.mu.Lock()
+	if f.down {
+		f.mu.Unlock()
+		return
+	}
+	f.conns[conn] = struct{}{}
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		delete(f.conns, conn)
+		f.mu.Unlock()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if _, err := io.WriteString(conn, f.reply(args)); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeRedis) reply(args []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "GET":
+		if args[1] == "wrongtype" {
+			return "-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"
+		}
+		value, ok := f.data[args[1]]
+		if !ok {
+			return "$-1\r\n"
+		}
+		return bulk(value)
+	case "SET":
+		f.data[args[1]] = args[2]
+		return "+OK\r\n"
+	case "DEL", "EXISTS":
+		count := 0
+		for _, key := range args[1:] {
+			if _, ok := f.data[key]; ok {
+				count++
+				if strings.EqualFold(args[0], "DEL") {
+					delete(f.data, key)
+				}
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", count)
+	case "SCAN":
+		pattern := "*"
+		for i := 2; i+1 < len(args); i += 2 {
+			if strings.EqualFold(args[i], "MATCH") {
+				pattern = args[i+1]
+			}
+		}
+		keys := []string{}
+		for key := range f.data {
+			if matchPattern(pattern, key) {
+				keys = append(keys, bulk(key))
+			}
+		}
+		return "*2\r\n" + bulk("0") + fmt.Sprintf("*%d\r\n", len(keys)) + strings.Join(keys, "")
+	case "PUBLISH":
+		return ":0\r\n"
+	}
+	return "-ERR unknown command\r\n"
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return nil, fmt.Errorf("unexpected %q", line)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(header[1:]))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
+
+func newTestFallbackCache(t *testing.T, f *fakeRedis) *FallbackCache {
+	t.Helper()
+
+	c := NewFallbackCache(f.client())
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestFallbackCacheUsesRedis(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRedis(t)
+	c := newTestFallbackCache(t, f)
+
+	if !c.available.Load() {
+		t.Fatal("cache started unavailable with Redis up")
+	}
+
+	if err := c.Set(ctx, "key", "value", time.Minute); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got, err := c.Get(ctx, "key"); err != nil || got != "value" {
+		t.Fatalf("Get = %q, %v, want value", got, err)
+	}
+	if !f.has("key") {
+		t.Fatal("Set did not reach Redis")
+	}
+}
+
+func TestFallbackCacheCheck(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name         string
+		call         func(c *FallbackCache) error
+		wantErr      error
+		wantFailover bool
+	}{
+		{
+			name: "miss",
+			call: func(c *FallbackCache) error {
+				_, err := c.Get(context.Background(), "missing")
+				return err
+			},
+			wantErr: ErrMiss,
+		},
+		{
+			name: "error reply",
+			call: func(c *FallbackCache) error {
+				_, err := c.Get(context.Background(), "wrongtype")
+				return err
+			},
+		},
+		{
+			name: "cancelled request",
+			call: func(c *FallbackCache) error {
+				_, err := c.Get(canceled, "key")
+				return err
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			name: "connection lost",
+			call: func(c *FallbackCache) error {
+				return c.Set(context.Background(), "key", "value", 0)
+			},
+			wantFailover: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeRedis(t)
+			c := newTestFallbackCache(t, f)
+			if tt.wantFailover {
+				f.setDown(true)
+			}
+
+			err := tt.call(c)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := !c.available.Load(); got != tt.wantFailover {
+				t.Fatalf("failed over = %v, want %v", got, tt.wantFailover)
+			}
+		})
+	}
+}
+
+func TestFallbackCacheFailsOverAndReconnects(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRedis(t)
+	c := newTestFallbackCache(t, f)
+
+	if err := c.Set(ctx, GetUserPRsKey(1, "epley"), "[]", 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := c.Set(ctx, "unrelated", "kept", 0); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	f.setDown(true)
+
+	if _, err := c.Get(ctx, "unrelated"); err == nil || errors.Is(err, ErrUnavailable) {
+		t.Fatalf("first Get after Redis went away = %v, want the network error", err)
+	}
+
+	// Once down, every call answers at once without touching Redis.
+	calls := map[string]func() error{
+		"Get":           func() error { _, err := c.Get(ctx, "unrelated"); return err },
+		"Set":           func() error { return c.Set(ctx, "key", "value", 0) },
+		"Delete":        func() error { return c.Delete(ctx, "key") },
+		"DeletePattern": func() error { return c.DeletePattern(ctx, "*") },
+		"Exists":        func() error { _, err := c.Exists(ctx, "key"); return err },
+		"Publish":       func() error { return c.Publish(ctx, "channel", "message") },
+		"Subscribe":     func() error { _, err := c.Subscribe(ctx, "channel"); return err },
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrUnavailable) {
+			t.Errorf("%s error = %v, want ErrUnavailable", name, err)
+		}
+	}
+
+	c.reconnect()
+	if c.available.Load() {
+		t.Fatal("reconnected while Redis was still down")
+	}
+
+	f.setDown(false)
+	c.reconnect()
+	if !c.available.Load() {
+		t.Fatal("did not reconnect once Redis was back")
+	}
+
+	if f.has(GetUserPRsKey(1, "epley")) {
+		t.Error("stale PR entry survived the reconnect")
+	}
+	if got, err := c.Get(ctx, "unrelated"); err != nil || got != "kept" {
+		t.Errorf("Get(unrelated) = %q, %v, want the entry outside the flushed patterns kept", got, err)
+	}
+}
+
+func TestFallbackCacheStartsUnavailable(t *testing.T) {
+	f := newFakeRedis(t)
+	f.setDown(true)
+
+	c := newTestFallbackCache(t, f)
+	if c.available.Load() {
+		t.Fatal("cache started available with Redis down")
+	}
+	if _, err := c.Get(context.Background(), "key"); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("Get error = %v, want ErrUnavailable", err)
+	}
+}
@@ -0,0 +1,275 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMemoryMaxEntries bounds a MemoryCache created without a size.
+const DefaultMemoryMaxEntries = 10000
+
+// memorySubscriberBuffer is how many messages a slow subscriber may fall
+// behind before further messages to it are dropped, as Redis does.
+const memorySubscriberBuffer = 100
+
+// MemoryCache keeps entries in this process, evicting the least recently
+// used once it holds maxEntries. Expired entries are dropped when read.
+// Published messages only reach subscribers in the same process.
+type MemoryCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // front is most recently used
+
+	subsMu      sync.Mutex
+	subscribers map[string]map[*memorySubscriber]struct{}
+}
+
+type memoryEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time // zero means no expiry
+}
+
+type memorySubscriber struct {
+	messages chan string
+	closed   bool
+}
+
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryMaxEntries
+	}
+
+	return &MemoryCache{
+		maxEntries:  maxEntries,
+		entries:     make(map[string]*list.Element),
+		order:       list.New(),
+		subscribers: make(map[string]map[*memorySubscriber]struct{}),
+	}
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := c.lookup(key, time.Now())
+	if entry == nil {
+		return "", ErrMiss
+	}
+	return entry.value, nil
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	entry := &memoryEntry{key: key, value: stringValue(value)}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *MemoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := c.entries[key]; ok {
+			c.remove(element)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) DeletePattern(ctx context.Context, pattern string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.entries {
+		if matchPattern(pattern, key) {
+			c.remove(element)
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lookup(key, time.Now()) != nil, nil
+}
+
+// Publish never blocks; a subscriber whose buffer is full misses the message.
+func (c *MemoryCache) Publish(ctx context.Context, channel string, message interface{}) error {
+	payload := stringValue(message)
+
+	c.subsMu.Lock()
+	defer c.subsMu.Unlock()
+
+	for sub := range c.subscribers[channel] {
+		select {
+		case sub.messages <- payload:
+		default:
+		}
+	}
+	return nil
+}
+
+func (c *MemoryCache) Subscribe(ctx context.Context, channel string) (*Subscription, error) {
+	sub := &memorySubscriber{messages: make(chan string, memorySubscriberBuffer)}
+
+	c.subsMu.Lock()
+	if c.subscribers[channel] == nil {
+		c.subscribers[channel] = make(map[*memorySubscriber]struct{})
+	}
+	c.subscribers[channel][sub] = struct{}{}
+	c.subsMu.Unlock()
+
+	closeFn := func() error {
+		c.subsMu.Lock()
+		defer c.subsMu.Unlock()
+
+		if sub.closed {
+			return nil
+		}
+		sub.closed = true
+
+		delete(c.subscribers[channel], sub)
+		if len(c.subscribers[channel]) == 0 {
+			delete(c.subscribers, channel)
+		}
+		close(sub.messages)
+		return nil
+	}
+
+	return &Subscription{messages: sub.messages, close: closeFn}, nil
+}
+
+func (c *MemoryCache) Close() error {
+	return nil
+}
+
+// lookup returns the live entry for key and marks it recently used. The
+// caller holds c.mu.
+func (c *MemoryCache) lookup(key string, now time.Time) *memoryEntry {
+	element, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if !entry.expiresAt.IsZero() && !now.Before(entry.expiresAt) {
+		c.remove(element)
+		return nil
+	}
+
+	c.order.MoveToFront(element)
+	return entry
+}
+
+func (c *MemoryCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*memoryEntry).key)
+}
+
+// matchPattern matches key against a glob the way Redis SCAN MATCH does:
+// * matches any run of bytes, slashes included, ? any one byte, [...] one
+// byte from a set or range ([^...] negates) and \ escapes the next byte.
+// Keys embed timezones such as America/New_York, so path.Match, whose *
+// stops at a slash, would not do.
+func matchPattern(pattern, key string) bool {
+	for pattern != "" {
+		switch pattern[0] {
+		case '*':
+			pattern = strings.TrimLeft(pattern, "*")
+			if pattern == "" {
+				return true
+			}
+			for i := 0; i <= len(key); i++ {
+				if matchPattern(pattern, key[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if key == "" {
+				return false
+			}
+		case '[':
+			if key == "" {
+				return false
+			}
+			end, ok := matchClass(pattern[1:], key[0])
+			if !ok {
+				return false
+			}
+			pattern = pattern[1+end:]
+			key = key[1:]
+			continue
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if key == "" || key[0] != pattern[0] {
+				return false
+			}
+		}
+		pattern = pattern[1:]
+		key = key[1:]
+	}
+	return key == ""
+}
+
+// matchClass reports whether b is in the class that class opens, just past
+// its [, and how many bytes of class the set and its ] take. An unterminated
+// class matches nothing.
+func matchClass(class string, b byte) (int, bool) {
+	i := 0
+	negate := i < len(class) && class[i] == '^'
+	if negate {
+		i++
+	}
+
+	matched := false
+	for first := true; i < len(class); first = false {
+		c := class[i]
+		if c == ']' && !first {
+			return i + 1, matched != negate
+		}
+		if c == '\\' && i+1 < len(class) {
+			i++
+			c = class[i]
+		}
+		if i+2 < len(class) && class[i+1] == '-' && class[i+2] != ']' {
+			lo, hi := c, class[i+2]
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			matched = matched || (lo <= b && b <= hi)
+			i += 3
+			continue
+		}
+		matched = matched || c == b
+		i++
+	}
+	return 0, false
+}
@@ -0,0 +1,281 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheGetSet(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+
+	if _, err := c.Get(ctx, "missing"); !errors.Is(err, ErrMiss) {
+		t.Fatalf("Get(missing) error = %v, want ErrMiss", err)
+	}
+
+	values := []struct {
+		key   string
+		value interface{}
+		want  string
+	}{
+		{"string", "hello", "hello"},
+		{"bytes", []byte(`{"id":1}`), `{"id":1}`},
+		{"number", 42, "42"},
+	}
+
+	for _, v := range values {
+		if err := c.Set(ctx, v.key, v.value, 0); err != nil {
+			t.Fatalf("Set(%s): %v", v.key, err)
+		}
+		got, err := c.Get(ctx, v.key)
+		if err != nil || got != v.want {
+			t.Errorf("Get(%s) = %q, %v, want %q", v.key, got, err, v.want)
+		}
+	}
+
+	if err := c.Delete(ctx, "string", "bytes", "missing"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	for _, key := range []string{"string", "bytes"} {
+		if exists, _ := c.Exists(ctx, key); exists {
+			t.Errorf("%s still exists after Delete", key)
+		}
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	tests := []struct {
+		name    string
+		ops     []string
+		present []string
+		evicted []string
+	}{
+		{
+			name:    "least recently set goes first",
+			ops:     []string{"set a", "set b", "set c", "set d"},
+			present: []string{"b", "c", "d"},
+			evicted: []string{"a"},
+		},
+		{
+			name:    "get refreshes",
+			ops:     []string{"set a", "set b", "set c", "get a", "set d"},
+			present: []string{"a", "c", "d"},
+			evicted: []string{"b"},
+		},
+		{
+			name:    "exists refreshes",
+			ops:     []string{"set a", "set b", "set c", "exists a", "set d"},
+			present: []string{"a", "c", "d"},
+			evicted: []string{"b"},
+		},
+		{
+			name:    "overwrite refreshes without growing",
+			ops:     []string{"set a", "set b", "set c", "set a", "set d"},
+			present: []string{"a", "c", "d"},
+			evicted: []string{"b"},
+		},
+		{
+			name:    "deleted entries free their slot",
+			ops:     []string{"set a", "set b", "set c", "delete b", "set d"},
+			present: []string{"a", "c", "d"},
+			evicted: []string{"b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := NewMemoryCache(3)
+
+			for _, op := range tt.ops {
+				verb, key, _ := strings.Cut(op, " ")
+				switch verb {
+				case "set":
+					c.Set(ctx, key, key, 0)
+				case "get":
+					c.Get(ctx, key)
+				case "exists":
+					c.Exists(ctx, key)
+				case "delete":
+					c.Delete(ctx, key)
+				}
+			}
+
+			// Checked through the map so the checks don't reorder entries.
+			for _, key := range tt.present {
+				if _, ok := c.entries[key]; !ok {
+					t.Errorf("%s was evicted", key)
+				}
+			}
+			for _, key := range tt.evicted {
+				if _, ok := c.entries[key]; ok {
+					t.Errorf("%s was kept", key)
+				}
+			}
+			if c.order.Len() != len(c.entries) {
+				t.Errorf("order has %d entries, map has %d", c.order.Len(), len(c.entries))
+			}
+		})
+	}
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+
+	c.Set(ctx, "short", "v", 10*time.Millisecond)
+	c.Set(ctx, "long", "v", time.Hour)
+	c.Set(ctx, "forever", "v", 0)
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := c.Get(ctx, "short"); !errors.Is(err, ErrMiss) {
+		t.Errorf("Get(short) error = %v, want ErrMiss after expiry", err)
+	}
+	if _, ok := c.entries["short"]; ok {
+		t.Error("expired entry was not dropped when read")
+	}
+	if exists, _ := c.Exists(ctx, "long"); !exists {
+		t.Error("long expired early")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	later := time.Now().Add(2 * time.Hour)
+	if c.lookup("long", later) != nil {
+		t.Error("long is still live after its TTL")
+	}
+	if c.lookup("forever", later) == nil {
+		t.Error("an entry without a TTL expired")
+	}
+}
+
+func TestMemoryCacheDeletePattern(t *testing.T) {
+	keys := []string{
+		GetUserPRsKey(1, "epley"),
+		GetUserPRsKey(1, "brzycki"),
+		GetUserPRsKey(2, "epley"),
+		GetExerciseProgressKey(1, "America/New_York", 5, 30, "epley"),
+		GetExerciseProgressKey(1, "UTC", 6, 30, "epley"),
+		GetWeeklySummaryKey(1, "Europe/London", "2024-W10"),
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		deleted []string
+	}{
+		{"user's PRs", GetUserPRsPattern(1), keys[0:2]},
+		{"timezone with a slash", GetExerciseProgressDefinitionPattern(1, 5), keys[3:4]},
+		{"all progress", GetExerciseProgressPattern(1), keys[3:5]},
+		{"weekly", GetWeeklySummaryPattern(1), keys[5:6]},
+		{"everything", "*", keys},
+		{"nothing", "nomatch:*", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := NewMemoryCache(100)
+			for _, key := range keys {
+				c.Set(ctx, key, "v", 0)
+			}
+
+			if err := c.DeletePattern(ctx, tt.pattern); err != nil {
+				t.Fatalf("DeletePattern(%q): %v", tt.pattern, err)
+			}
+
+			deleted := map[string]bool{}
+			for _, key := range tt.deleted {
+				deleted[key] = true
+			}
+			for _, key := range keys {
+				exists, _ := c.Exists(ctx, key)
+				if exists == deleted[key] {
+					t.Errorf("%s exists = %v after DeletePattern(%q)", key, exists, tt.pattern)
+				}
+			}
+		})
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		key     string
+		want    bool
+	}{
+		{"prs:*", "prs:user:1:epley", true},
+		{"prs:*", "progress:user:1", false},
+		{"*:epley", "prs:user:1:epley", true},
+		{"a*b*c", "a/x/b/y/c", true},
+		{"a*b*c", "a/x/c", false},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-c]llo", "hbllo", true},
+		{"h[a-c]llo", "hdllo", false},
+		{"h[]]llo", "h]llo", true},
+		{`a\*b`, "a*b", true},
+		{`a\*b`, "axb", false},
+		{"h[ello", "hello", false},
+		{"", "", true},
+		{"", "a", false},
+		{"exact", "exact", true},
+	}
+
+	for _, tt := range tests {
+		if got := matchPattern(tt.pattern, tt.key); got != tt.want {
+			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryCachePubSub(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(10)
+
+	first, _ := c.Subscribe(ctx, "events")
+	second, _ := c.Subscribe(ctx, "events")
+	other, _ := c.Subscribe(ctx, "other")
+
+	c.Publish(ctx, "events", "hello")
+
+	for name, sub := range map[string]*Subscription{"first": first, "second": second} {
+		select {
+		case got := <-sub.Messages():
+			if got != "hello" {
+				t.Errorf("%s got %q, want hello", name, got)
+			}
+		default:
+			t.Errorf("%s got nothing", name)
+		}
+	}
+
+	select {
+	case got := <-other.Messages():
+		t.Errorf("other channel got %q", got)
+	default:
+	}
+
+	first.Close()
+	first.Close()
+	if _, ok := <-first.Messages(); ok {
+		t.Error("closed subscription still delivers")
+	}
+
+	// A subscriber that stops reading drops messages instead of blocking.
+	for i := 0; i < memorySubscriberBuffer+10; i++ {
+		c.Publish(ctx, "events", "flood")
+	}
+	if got := len(second.Messages()); got != memorySubscriberBuffer {
+		t.Errorf("buffered %d messages, want %d", got, memorySubscriberBuffer)
+	}
+}
@@ -1,89 +1,81 @@
 package cache
 
 import (
-	"context"
 	"fmt"
 	"time"
-
-	"github.com/redis/go-redis/v9"
 )
 
 const (
-	KeyActiveWorkout   = "active_workout:user:%d"
-	KeyUserPRs         = "prs:user:%d"
-	KeyWeeklySummary   = "weekly:user:%d:week:%s"
-	KeyExerciseProgress = "progress:user:%d:exercise:%s"
+	KeyActiveWorkout    = "active_workout:user:%d"
+	KeyRestTimer        = "rest_timer:user:%d"
+	KeyUserPRs          = "prs:user:%d:formula:%s"
+	KeyWeeklySummary    = "weekly:user:%d:tz:%s:week:%s"
+	KeyExerciseProgress = "progress:user:%d:tz:%s:exercise:%d:days:%d:formula:%s"
+	KeyRevokedToken     = "revoked_token:%s"
+	KeyUserPreferences  = "prefs:user:%d"
 )
 
 const (
-	TTLActiveWorkout   = 24 * time.Hour
-	TTLUserPRs         = 1 * time.Hour
-	TTLWeeklySummary   = 7 * 24 * time.Hour
+	TTLActiveWorkout    = 24 * time.Hour
+	TTLRestTimer        = 2 * time.Hour
+	TTLUserPRs          = 1 * time.Hour
+	TTLWeeklySummary    = 7 * 24 * time.Hour
 	TTLExerciseProgress = 1 * time.Hour
+	TTLUserPreferences  = 24 * time.Hour
+
+	// TTLTokenNotRevoked bounds how long a cached "not revoked" answer can
+	// hide a revocation whose cache write failed.
+	TTLTokenNotRevoked = 30 * time.Second
 )
 
-type Cache struct {
-	client *redis.Client
+func GetActiveWorkoutKey(userID int) string {
+	return fmt.Sprintf(KeyActiveWorkout, userID)
 }
 
-func NewCache(client *redis.Client) *Cache {
-	return &Cache{client: client}
+func GetRestTimerKey(userID int) string {
+	return fmt.Sprintf(KeyRestTimer, userID)
 }
 
-func (c *Cache) Get(ctx context.Context, key string) (string, error) {
-	return c.client.Get(ctx, key).Result()
+func GetUserPRsKey(userID int, formula string) string {
+	return fmt.Sprintf(KeyUserPRs, userID, formula)
 }
 
-func (c *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
-	return c.client.Set(ctx, key, value, ttl).Err()
+// GetUserPRsPattern matches the user's PR cache entries for every formula.
+func GetUserPRsPattern(userID int) string {
+	return fmt.Sprintf("prs:user:%d:*", userID)
 }
 
-func (c *Cache) Delete(ctx context.Context, keys ...string) error {
-	if len(keys) == 0 {
-		return nil
-	}
-	return c.client.Del(ctx, keys...).Err()
+// Weekly and progress entries are bucketed by day, so the key carries the
+// timezone they were computed in; changing zones never serves stale buckets.
+func GetWeeklySummaryKey(userID int, timezone string, week string) string {
+	return fmt.Sprintf(KeyWeeklySummary, userID, timezone, week)
 }
 
-func (c *Cache) DeletePattern(ctx context.Context, pattern string) error {
-	iter := c.client.Scan(ctx, 0, pattern, 0).Iterator()
-	var keys []string
-
-	for iter.Next(ctx) {
-		keys = append(keys, iter.Val())
-	}
-
-	if err := iter.Err(); err != nil {
-		return err
-	}
-
-	if len(keys) > 0 {
-		return c.client.Del(ctx, keys...).Err()
-	}
-
-	return nil
+func GetExerciseProgressKey(userID int, timezone string, definitionID int, days int, formula string) string {
+	return fmt.Sprintf(KeyExerciseProgress, userID, timezone, definitionID, days, formula)
 }
 
-func (c *Cache) Exists(ctx context.Context, key string) (bool, error) {
-	count, err := c.client.Exists(ctx, key).Result()
-	if err != nil {
-		return false, err
-	}
-	return count > 0, nil
+// GetWeeklySummaryPattern matches the user's weekly summaries in every
+// timezone and week.
+func GetWeeklySummaryPattern(userID int) string {
+	return fmt.Sprintf("weekly:user:%d:*", userID)
 }
 
-func GetActiveWorkoutKey(userID int) string {
-	return fmt.Sprintf(KeyActiveWorkout, userID)
+// GetExerciseProgressPattern matches every progress series cached for the user.
+func GetExerciseProgressPattern(userID int) string {
+	return fmt.Sprintf("progress:user:%d:*", userID)
 }
 
-func GetUserPRsKey(userID int) string {
-	return fmt.Sprintf(KeyUserPRs, userID)
+// GetExerciseProgressDefinitionPattern matches the user's progress series for
+// one exercise in every timezone, window and formula.
+func GetExerciseProgressDefinitionPattern(userID int, definitionID int) string {
+	return fmt.Sprintf("progress:user:%d:tz:*:exercise:%d:*", userID, definitionID)
 }
 
-func GetWeeklySummaryKey(userID int, week string) string {
-	return fmt.Sprintf(KeyWeeklySummary, userID, week)
+func GetUserPreferencesKey(userID int) string {
+	return fmt.Sprintf(KeyUserPreferences, userID)
 }
 
-func GetExerciseProgressKey(userID int, exerciseName string) string {
-	return fmt.Sprintf(KeyExerciseProgress, userID, exerciseName)
+func GetRevokedTokenKey(jti string) string {
+	return fmt.Sprintf(KeyRevokedToken, jti)
 }
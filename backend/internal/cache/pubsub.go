@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+const ChannelWorkoutEvents = "workout_events:user:%d"
+
+func GetWorkoutEventsChannel(userID int) string {
+	return fmt.Sprintf(ChannelWorkoutEvents, userID)
+}
+
+// Subscription receives messages published to one channel, from every API
+// instance when the cache is Redis. Close it to stop receiving.
+type Subscription struct {
+	messages <-chan string
+	close    func() error
+}
+
+func (s *Subscription) Messages() <-chan string {
+	return s.messages
+}
+
+func (s *Subscription) Close() error {
+	return s.close()
+}
+
+func (c *RedisCache) Publish(ctx context.Context, channel string, message interface{}) error {
+	return c.client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe returns once Redis has confirmed the subscription, so nothing
+// published after it returns is missed.
+func (c *RedisCache) Subscribe(ctx context.Context, channel string) (*Subscription, error) {
+	pubsub := c.client.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	messages := make(chan string)
+	done := make(chan struct{})
+	go func() {
+		defer close(messages)
+		for msg := range pubsub.Channel() {
+			select {
+			case messages <- msg.Payload:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	closeFn := func() error {
+		once.Do(func() { close(done) })
+		return pubsub.Close()
+	}
+
+	return &Subscription{messages: messages, close: closeFn}, nil
+}
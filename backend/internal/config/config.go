@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/joho/godotenv"
@@ -14,8 +15,11 @@ type Config struct {
 
 	Database DatabaseConfig
 	Redis    RedisConfig
+	Cache    CacheConfig
 	JWT      JWTConfig
 	CORS     CORSConfig
+	Export   ExportConfig
+	Workout  WorkoutConfig
 
 	LogLevel string
 }
@@ -27,6 +31,8 @@ type DatabaseConfig struct {
 	Password string
 	Name     string
 	SSLMode  string
+
+	RequireCurrentSchema bool
 }
 
 type RedisConfig struct {
@@ -36,23 +42,51 @@ type RedisConfig struct {
 	DB       int
 }
 
+// CacheConfig.Driver is "redis" or "memory". MemoryMaxEntries bounds the
+// memory cache. RedisFallback lets the API start and keep serving from the
+// database while Redis is unreachable.
+type CacheConfig struct {
+	Driver           string
+	MemoryMaxEntries int
+	RedisFallback    bool
+}
+
 type JWTConfig struct {
-	Secret      string
-	ExpiryHours int
+	Secret              string
+	AccessExpiryMinutes int
+	RefreshExpiryHours  int
 }
 
 type CORSConfig struct {
 	AllowedOrigins string
 }
 
+// ExportConfig.Dir is scratch space for archives while they are built;
+// finished archives are stored in Postgres.
+type ExportConfig struct {
+	Dir            string
+	RetentionHours int
+}
+
+// WorkoutConfig.AutoAbandonHours is how long a workout may stay unfinished
+// before it is abandoned automatically.
+type WorkoutConfig struct {
+	AutoAbandonHours int
+}
+
 func Load() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		fmt.Println("Warning: .env file not found, using environment variables")
 	}
 
-	jwtExpiryHours, err := strconv.Atoi(getEnv("JWT_EXPIRY_HOURS", "168"))
+	jwtAccessExpiryMinutes, err := strconv.Atoi(getEnv("JWT_ACCESS_EXPIRY_MINUTES", "15"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT_ACCESS_EXPIRY_MINUTES: %w", err)
+	}
+
+	jwtRefreshExpiryHours, err := strconv.Atoi(getEnv("JWT_REFRESH_EXPIRY_HOURS", "720"))
 	if err != nil {
-		return nil, fmt.Errorf("invalid JWT_EXPIRY_HOURS: %w", err)
+		return nil, fmt.Errorf("invalid JWT_REFRESH_EXPIRY_HOURS: %w", err)
 	}
 
 	redisDB, err := strconv.Atoi(getEnv("REDIS_DB", "0"))
@@ -60,6 +94,31 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid REDIS_DB: %w", err)
 	}
 
+	cacheMemoryMaxEntries, err := strconv.Atoi(getEnv("CACHE_MEMORY_MAX_ENTRIES", "10000"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_MEMORY_MAX_ENTRIES: %w", err)
+	}
+
+	cacheRedisFallback, err := strconv.ParseBool(getEnv("CACHE_REDIS_FALLBACK", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_REDIS_FALLBACK: %w", err)
+	}
+
+	requireCurrentSchema, err := strconv.ParseBool(getEnv("DB_REQUIRE_CURRENT_SCHEMA", "false"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_REQUIRE_CURRENT_SCHEMA: %w", err)
+	}
+
+	exportRetentionHours, err := strconv.Atoi(getEnv("EXPORT_RETENTION_HOURS", "24"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EXPORT_RETENTION_HOURS: %w", err)
+	}
+
+	workoutAutoAbandonHours, err := strconv.Atoi(getEnv("WORKOUT_AUTO_ABANDON_HOURS", "24"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WORKOUT_AUTO_ABANDON_HOURS: %w", err)
+	}
+
 	config := &Config{
 		Port:        getEnv("PORT", "8080"),
 		Environment: getEnv("ENV", "development"),
@@ -71,6 +130,8 @@ func Load() (*Config, error) {
 			Password: getEnv("DB_PASSWORD", ""),
 			Name:     getEnv("DB_NAME", "cadence"),
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+
+			RequireCurrentSchema: requireCurrentSchema,
 		},
 
 		Redis: RedisConfig{
@@ -80,15 +141,31 @@ func Load() (*Config, error) {
 			DB:       redisDB,
 		},
 
+		Cache: CacheConfig{
+			Driver:           getEnv("CACHE_DRIVER", "redis"),
+			MemoryMaxEntries: cacheMemoryMaxEntries,
+			RedisFallback:    cacheRedisFallback,
+		},
+
 		JWT: JWTConfig{
-			Secret:      getEnv("JWT_SECRET", ""),
-			ExpiryHours: jwtExpiryHours,
+			Secret:              getEnv("JWT_SECRET", ""),
+			AccessExpiryMinutes: jwtAccessExpiryMinutes,
+			RefreshExpiryHours:  jwtRefreshExpiryHours,
 		},
 
 		CORS: CORSConfig{
 			AllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173"),
 		},
 
+		Export: ExportConfig{
+			Dir:            getEnv("EXPORT_DIR", filepath.Join(os.TempDir(), "cadence-exports")),
+			RetentionHours: exportRetentionHours,
+		},
+
+		Workout: WorkoutConfig{
+			AutoAbandonHours: workoutAutoAbandonHours,
+		},
+
 		LogLevel: getEnv("LOG_LEVEL", "info"),
 	}
 
@@ -117,5 +194,23 @@ func validate(config *Config) error {
 	if len(config.JWT.Secret) < 32 {
 		return fmt.Errorf("JWT_SECRET must be at least 32 characters")
 	}
+	if config.JWT.AccessExpiryMinutes <= 0 {
+		return fmt.Errorf("JWT_ACCESS_EXPIRY_MINUTES must be greater than 0")
+	}
+	if config.JWT.RefreshExpiryHours <= 0 {
+		return fmt.Errorf("JWT_REFRESH_EXPIRY_HOURS must be greater than 0")
+	}
+	if config.Cache.Driver != "redis" && config.Cache.Driver != "memory" {
+		return fmt.Errorf("CACHE_DRIVER must be redis or memory")
+	}
+	if config.Cache.MemoryMaxEntries <= 0 {
+		return fmt.Errorf("CACHE_MEMORY_MAX_ENTRIES must be greater than 0")
+	}
+	if config.Export.RetentionHours <= 0 {
+		return fmt.Errorf("EXPORT_RETENTION_HOURS must be greater than 0")
+	}
+	if config.Workout.AutoAbandonHours <= 0 {
+		return fmt.Errorf("WORKOUT_AUTO_ABANDON_HOURS must be greater than 0")
+	}
 	return nil
 }
@@ -0,0 +1,113 @@
+package migrations
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func migrationFS(names ...string) fstest.MapFS {
+	files := fstest.MapFS{}
+	for _, name := range names {
+		files["sql/"+name] = &fstest.MapFile{Data: []byte("-- " + name)}
+	}
+	return files
+}
+
+func versionsOf(migrations []Migration) []int {
+	versions := []int{}
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+func TestLoadMigrationsOrdersByVersion(t *testing.T) {
+	// "10_" sorts before "2_" by name; the result must still be numeric.
+	files := migrationFS(
+		"10_ten.up.sql", "10_ten.down.sql",
+		"2_two.up.sql", "2_two.down.sql",
+		"0001_one.up.sql", "0001_one.down.sql",
+	)
+
+	migrations, err := loadMigrations(files)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+
+	if got, want := versionsOf(migrations), []int{1, 2, 10}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("versions = %v, want %v", got, want)
+	}
+	if migrations[2].Name != "ten" || migrations[2].Up != "-- 10_ten.up.sql" || migrations[2].Down != "-- 10_ten.down.sql" {
+		t.Fatalf("migration 10 = %+v", migrations[2])
+	}
+}
+
+func TestLoadMigrationsRejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  string
+	}{
+		{"missing down", migrationFS("0001_one.up.sql"), "must have both up and down"},
+		{"bad suffix", migrationFS("0001_one.sql"), "invalid migration filename"},
+		{"no name", migrationFS("0001.up.sql"), "invalid migration filename"},
+		{"bad version", migrationFS("abc_one.up.sql"), "invalid migration version"},
+		{"zero version", migrationFS("0000_zero.up.sql"), "invalid migration version"},
+		{"mismatched names", migrationFS("0001_one.up.sql", "0001_uno.down.sql"), "mismatched names"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMigrations(tt.files)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}}
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		applied []int
+		want    []int
+	}{
+		{"none applied", nil, []int{1, 2, 3, 4}},
+		{"all applied", []int{1, 2, 3, 4}, []int{}},
+		{"prefix applied", []int{1, 2}, []int{3, 4}},
+		{"gaps stay in order", []int{1, 3}, []int{2, 4}},
+		{"unknown versions ignored", []int{1, 2, 3, 4, 99}, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applied := map[int]time.Time{}
+			for _, version := range tt.applied {
+				applied[version] = now
+			}
+
+			if got := versionsOf(pendingMigrations(migrations, applied)); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("pending = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Fatalf("migration %d has version %d; versions must be contiguous", i, migration.Version)
+		}
+	}
+}
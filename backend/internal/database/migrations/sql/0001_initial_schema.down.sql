@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS sets;
+DROP TABLE IF EXISTS exercises;
+DROP TABLE IF EXISTS workout_sessions;
+DROP TABLE IF EXISTS users;
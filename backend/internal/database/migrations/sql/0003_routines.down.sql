@@ -0,0 +1,3 @@
+ALTER TABLE sets DROP COLUMN IF EXISTS is_completed;
+DROP TABLE IF EXISTS routine_exercises;
+DROP TABLE IF EXISTS routines;
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS routines (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_routines_user_id ON routines(user_id);
+
+CREATE TABLE IF NOT EXISTS routine_exercises (
+    id SERIAL PRIMARY KEY,
+    routine_id INTEGER NOT NULL REFERENCES routines(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    order_index INTEGER NOT NULL DEFAULT 0,
+    target_sets INTEGER NOT NULL,
+    target_reps INTEGER NOT NULL,
+    target_weight DECIMAL(10, 2),
+    is_bodyweight BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_target_sets CHECK (target_sets > 0),
+    CONSTRAINT chk_target_reps CHECK (target_reps > 0),
+    CONSTRAINT chk_target_weight CHECK (target_weight IS NULL OR target_weight >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_routine_exercises_routine_id ON routine_exercises(routine_id);
+
+-- Sets pre-populated from a routine start out as targets rather than work
+-- that was actually performed.
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS is_completed BOOLEAN NOT NULL DEFAULT true;
@@ -0,0 +1,3 @@
+ALTER TABLE routine_exercises DROP COLUMN IF EXISTS exercise_definition_id;
+ALTER TABLE exercises DROP COLUMN IF EXISTS exercise_definition_id;
+DROP TABLE IF EXISTS exercise_definitions;
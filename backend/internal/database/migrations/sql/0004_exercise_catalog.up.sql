@@ -0,0 +1,123 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Definitions with a NULL user_id are the shared library; the rest are
+-- custom exercises visible only to their owner.
+CREATE TABLE IF NOT EXISTS exercise_definitions (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    primary_muscles TEXT[] NOT NULL DEFAULT '{}',
+    equipment VARCHAR(50) NOT NULL DEFAULT 'other',
+    is_bodyweight BOOLEAN NOT NULL DEFAULT false,
+    unit_type VARCHAR(20) NOT NULL DEFAULT 'weight_reps',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_unit_type CHECK (unit_type IN ('weight_reps', 'reps', 'duration', 'distance'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercise_definitions_library_name
+    ON exercise_definitions (LOWER(name)) WHERE user_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercise_definitions_user_name
+    ON exercise_definitions (user_id, LOWER(name)) WHERE user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_exercise_definitions_name_trgm
+    ON exercise_definitions USING GIN (name gin_trgm_ops);
+
+-- Aliases are stored lowercase so lookups can compare against LOWER(input).
+INSERT INTO exercise_definitions (name, aliases, primary_muscles, equipment, is_bodyweight, unit_type) VALUES
+    ('Bench Press', '{"bench","barbell bench","barbell bench press","flat bench press","bb bench press"}', '{"chest","triceps","shoulders"}', 'barbell', false, 'weight_reps'),
+    ('Incline Bench Press', '{"incline bench","incline barbell bench press"}', '{"chest","shoulders","triceps"}', 'barbell', false, 'weight_reps'),
+    ('Dumbbell Bench Press', '{"db bench press","dumbbell bench","db bench"}', '{"chest","triceps","shoulders"}', 'dumbbell', false, 'weight_reps'),
+    ('Incline Dumbbell Press', '{"incline db press","incline dumbbell bench press"}', '{"chest","shoulders","triceps"}', 'dumbbell', false, 'weight_reps'),
+    ('Overhead Press', '{"ohp","military press","standing press","barbell overhead press"}', '{"shoulders","triceps"}', 'barbell', false, 'weight_reps'),
+    ('Dumbbell Shoulder Press', '{"db shoulder press","seated dumbbell press"}', '{"shoulders","triceps"}', 'dumbbell', false, 'weight_reps'),
+    ('Push-up', '{"push-ups","pushup","pushups","push up","push ups"}', '{"chest","triceps","shoulders"}', 'bodyweight', true, 'reps'),
+    ('Dip', '{"dips","chest dip","tricep dip"}', '{"chest","triceps"}', 'bodyweight', true, 'reps'),
+    ('Squat', '{"squats","back squat","barbell squat","barbell back squat"}', '{"quads","glutes","hamstrings"}', 'barbell', false, 'weight_reps'),
+    ('Front Squat', '{"front squats","barbell front squat"}', '{"quads","glutes","core"}', 'barbell', false, 'weight_reps'),
+    ('Goblet Squat', '{"goblet squats"}', '{"quads","glutes"}', 'dumbbell', false, 'weight_reps'),
+    ('Leg Press', '{"leg presses"}', '{"quads","glutes"}', 'machine', false, 'weight_reps'),
+    ('Deadlift', '{"deadlifts","conventional deadlift","barbell deadlift"}', '{"back","hamstrings","glutes"}', 'barbell', false, 'weight_reps'),
+    ('Romanian Deadlift', '{"rdl","rdls","romanian deadlifts"}', '{"hamstrings","glutes","back"}', 'barbell', false, 'weight_reps'),
+    ('Sumo Deadlift', '{"sumo deadlifts"}', '{"glutes","hamstrings","back"}', 'barbell', false, 'weight_reps'),
+    ('Hip Thrust', '{"hip thrusts","barbell hip thrust"}', '{"glutes","hamstrings"}', 'barbell', false, 'weight_reps'),
+    ('Lunge', '{"lunges","walking lunge","walking lunges"}', '{"quads","glutes"}', 'dumbbell', false, 'weight_reps'),
+    ('Bulgarian Split Squat', '{"split squat","bulgarian split squats"}', '{"quads","glutes"}', 'dumbbell', false, 'weight_reps'),
+    ('Leg Extension', '{"leg extensions"}', '{"quads"}', 'machine', false, 'weight_reps'),
+    ('Leg Curl', '{"leg curls","lying leg curl","seated leg curl"}', '{"hamstrings"}', 'machine', false, 'weight_reps'),
+    ('Calf Raise', '{"calf raises","standing calf raise"}', '{"calves"}', 'machine', false, 'weight_reps'),
+    ('Pull-up', '{"pull-ups","pullup","pullups","pull up","pull ups"}', '{"back","biceps"}', 'bodyweight', true, 'reps'),
+    ('Chin-up', '{"chin-ups","chinup","chinups","chin up","chin ups"}', '{"back","biceps"}', 'bodyweight', true, 'reps'),
+    ('Lat Pulldown', '{"lat pulldowns","pulldown","lat pull down"}', '{"back","biceps"}', 'cable', false, 'weight_reps'),
+    ('Barbell Row', '{"barbell rows","bent over row","bent-over row","bb row"}', '{"back","biceps"}', 'barbell', false, 'weight_reps'),
+    ('Dumbbell Row', '{"dumbbell rows","db row","one arm dumbbell row","single arm dumbbell row"}', '{"back","biceps"}', 'dumbbell', false, 'weight_reps'),
+    ('Seated Cable Row', '{"cable row","seated row"}', '{"back","biceps"}', 'cable', false, 'weight_reps'),
+    ('Face Pull', '{"face pulls"}', '{"shoulders","back"}', 'cable', false, 'weight_reps'),
+    ('Barbell Curl', '{"curl","curls","bicep curl","barbell curls"}', '{"biceps"}', 'barbell', false, 'weight_reps'),
+    ('Dumbbell Curl', '{"dumbbell curls","db curl","dumbbell bicep curl"}', '{"biceps"}', 'dumbbell', false, 'weight_reps'),
+    ('Hammer Curl', '{"hammer curls"}', '{"biceps","forearms"}', 'dumbbell', false, 'weight_reps'),
+    ('Tricep Pushdown', '{"tricep pushdowns","triceps pushdown","cable pushdown","rope pushdown"}', '{"triceps"}', 'cable', false, 'weight_reps'),
+    ('Skull Crusher', '{"skull crushers","lying tricep extension"}', '{"triceps"}', 'barbell', false, 'weight_reps'),
+    ('Lateral Raise', '{"lateral raises","side raise","side lateral raise","dumbbell lateral raise"}', '{"shoulders"}', 'dumbbell', false, 'weight_reps'),
+    ('Hanging Leg Raise', '{"hanging leg raises","leg raise"}', '{"core"}', 'bodyweight', true, 'reps'),
+    ('Plank', '{"planks"}', '{"core"}', 'bodyweight', true, 'duration'),
+    ('Kettlebell Swing', '{"kettlebell swings","kb swing"}', '{"glutes","hamstrings","back"}', 'kettlebell', false, 'weight_reps'),
+    ('Running', '{"run","treadmill run"}', '{"full_body"}', 'other', false, 'distance'),
+    ('Rowing Machine', '{"row erg","erg","rower"}', '{"full_body"}', 'machine', false, 'distance')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS exercise_definition_id INTEGER REFERENCES exercise_definitions(id);
+ALTER TABLE routine_exercises ADD COLUMN IF NOT EXISTS exercise_definition_id INTEGER REFERENCES exercise_definitions(id);
+
+-- Link existing free-text names to the library by name or alias.
+UPDATE exercises e
+SET exercise_definition_id = d.id
+FROM exercise_definitions d
+WHERE e.exercise_definition_id IS NULL
+    AND d.user_id IS NULL
+    AND (LOWER(d.name) = LOWER(TRIM(e.name)) OR LOWER(TRIM(e.name)) = ANY(d.aliases));
+
+UPDATE routine_exercises re
+SET exercise_definition_id = d.id
+FROM exercise_definitions d
+WHERE re.exercise_definition_id IS NULL
+    AND d.user_id IS NULL
+    AND (LOWER(d.name) = LOWER(TRIM(re.name)) OR LOWER(TRIM(re.name)) = ANY(d.aliases));
+
+-- Anything left over becomes a custom definition owned by the user who logged it.
+INSERT INTO exercise_definitions (user_id, name)
+SELECT DISTINCT ON (owner_id, LOWER(TRIM(exercise_name))) owner_id, TRIM(exercise_name)
+FROM (
+    SELECT ws.user_id AS owner_id, e.name AS exercise_name
+    FROM exercises e
+    JOIN workout_sessions ws ON ws.id = e.workout_session_id
+    WHERE e.exercise_definition_id IS NULL
+    UNION ALL
+    SELECT r.user_id AS owner_id, re.name AS exercise_name
+    FROM routine_exercises re
+    JOIN routines r ON r.id = re.routine_id
+    WHERE re.exercise_definition_id IS NULL
+) unmatched
+ORDER BY owner_id, LOWER(TRIM(exercise_name))
+ON CONFLICT (user_id, LOWER(name)) WHERE user_id IS NOT NULL DO NOTHING;
+
+UPDATE exercises e
+SET exercise_definition_id = d.id
+FROM workout_sessions ws, exercise_definitions d
+WHERE e.exercise_definition_id IS NULL
+    AND ws.id = e.workout_session_id
+    AND d.user_id = ws.user_id
+    AND LOWER(d.name) = LOWER(TRIM(e.name));
+
+UPDATE routine_exercises re
+SET exercise_definition_id = d.id
+FROM routines r, exercise_definitions d
+WHERE re.exercise_definition_id IS NULL
+    AND r.id = re.routine_id
+    AND d.user_id = r.user_id
+    AND LOWER(d.name) = LOWER(TRIM(re.name));
+
+ALTER TABLE exercises ALTER COLUMN exercise_definition_id SET NOT NULL;
+ALTER TABLE routine_exercises ALTER COLUMN exercise_definition_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_exercises_exercise_definition_id ON exercises(exercise_definition_id);
@@ -0,0 +1,10 @@
+ALTER TABLE sets DROP CONSTRAINT IF EXISTS chk_rest_seconds;
+ALTER TABLE sets DROP CONSTRAINT IF EXISTS chk_rir;
+ALTER TABLE sets DROP CONSTRAINT IF EXISTS chk_rpe;
+ALTER TABLE sets DROP CONSTRAINT IF EXISTS chk_set_type;
+
+ALTER TABLE sets DROP COLUMN IF EXISTS rest_seconds;
+ALTER TABLE sets DROP COLUMN IF EXISTS tempo;
+ALTER TABLE sets DROP COLUMN IF EXISTS rir;
+ALTER TABLE sets DROP COLUMN IF EXISTS rpe;
+ALTER TABLE sets DROP COLUMN IF EXISTS set_type;
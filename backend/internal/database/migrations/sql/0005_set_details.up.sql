@@ -0,0 +1,12 @@
+-- Set classification and effort tracking. rest_seconds is the rest taken
+-- before the set, so the first set of an exercise normally leaves it empty.
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS set_type VARCHAR(20) NOT NULL DEFAULT 'normal';
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS rpe DECIMAL(3, 1);
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS rir INTEGER;
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS tempo VARCHAR(4);
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS rest_seconds INTEGER;
+
+ALTER TABLE sets ADD CONSTRAINT chk_set_type CHECK (set_type IN ('normal', 'warmup', 'drop', 'amrap', 'failure'));
+ALTER TABLE sets ADD CONSTRAINT chk_rpe CHECK (rpe IS NULL OR (rpe >= 1 AND rpe <= 10));
+ALTER TABLE sets ADD CONSTRAINT chk_rir CHECK (rir IS NULL OR rir >= 0);
+ALTER TABLE sets ADD CONSTRAINT chk_rest_seconds CHECK (rest_seconds IS NULL OR rest_seconds >= 0);
@@ -0,0 +1,20 @@
+-- One row per record broken when a workout is completed. old_value is the
+-- record that stood before; e1RM events note the formula they were scored with.
+CREATE TABLE IF NOT EXISTS pr_events (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    workout_session_id INTEGER NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    exercise_definition_id INTEGER NOT NULL REFERENCES exercise_definitions(id) ON DELETE CASCADE,
+    set_id INTEGER REFERENCES sets(id) ON DELETE SET NULL,
+    category VARCHAR(20) NOT NULL,
+    rep_count INTEGER,
+    formula VARCHAR(20),
+    old_value DECIMAL(10, 2) NOT NULL,
+    new_value DECIMAL(10, 2) NOT NULL,
+    achieved_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_pr_category CHECK (category IN ('heaviest_single', 'rep_max', 'e1rm', 'most_reps', 'session_volume'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_events_user_achieved ON pr_events(user_id, achieved_at DESC);
+CREATE INDEX IF NOT EXISTS idx_pr_events_workout ON pr_events(workout_session_id);
@@ -0,0 +1,13 @@
+UPDATE pr_events
+SET old_value = old_value / 0.45359237, new_value = new_value / 0.45359237
+WHERE category <> 'most_reps';
+
+ALTER TABLE routine_exercises DROP CONSTRAINT IF EXISTS chk_routine_weight_unit;
+ALTER TABLE routine_exercises DROP COLUMN IF EXISTS weight_unit;
+
+ALTER TABLE sets DROP COLUMN IF EXISTS weight_kg;
+ALTER TABLE sets DROP CONSTRAINT IF EXISTS chk_weight_unit;
+ALTER TABLE sets DROP COLUMN IF EXISTS weight_unit;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_preferred_unit;
+ALTER TABLE users DROP COLUMN IF EXISTS preferred_unit;
@@ -0,0 +1,17 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS preferred_unit VARCHAR(2) NOT NULL DEFAULT 'lb';
+ALTER TABLE users ADD CONSTRAINT chk_preferred_unit CHECK (preferred_unit IN ('kg', 'lb'));
+
+-- Weights keep the unit they were entered in. weight_kg is the canonical
+-- value every aggregate is computed from, so mixed-unit history adds up.
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS weight_unit VARCHAR(2) NOT NULL DEFAULT 'lb';
+ALTER TABLE sets ADD CONSTRAINT chk_weight_unit CHECK (weight_unit IN ('kg', 'lb'));
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS weight_kg DECIMAL(10, 3)
+    GENERATED ALWAYS AS (CASE WHEN weight_unit = 'kg' THEN weight ELSE weight * 0.45359237 END) STORED;
+
+ALTER TABLE routine_exercises ADD COLUMN IF NOT EXISTS weight_unit VARCHAR(2) NOT NULL DEFAULT 'lb';
+ALTER TABLE routine_exercises ADD CONSTRAINT chk_routine_weight_unit CHECK (weight_unit IN ('kg', 'lb'));
+
+-- PR event values are kilograms from now on; existing rows were pounds.
+UPDATE pr_events
+SET old_value = old_value * 0.45359237, new_value = new_value * 0.45359237
+WHERE category <> 'most_reps';
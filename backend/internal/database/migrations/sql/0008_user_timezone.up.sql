@@ -0,0 +1,2 @@
+-- IANA zone name used to bucket workouts into days and ISO weeks.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,13 @@
+-- One row per edit made to a workout after it was completed. before and after
+-- are full snapshots of the workout, with its exercises and sets, as JSON.
+CREATE TABLE IF NOT EXISTS workout_revisions (
+    id SERIAL PRIMARY KEY,
+    workout_session_id INTEGER NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    action VARCHAR(30) NOT NULL,
+    before JSONB NOT NULL,
+    after JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_revisions_workout ON workout_revisions(workout_session_id, created_at DESC);
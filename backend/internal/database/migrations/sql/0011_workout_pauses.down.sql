@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS workout_pauses;
+
+-- The old schema cannot represent discarded workouts, so they are dropped.
+UPDATE workout_sessions SET status = 'active' WHERE status = 'paused';
+DELETE FROM workout_sessions WHERE status = 'abandoned';
+
+ALTER TABLE workout_sessions DROP COLUMN IF EXISTS abandoned_at;
+ALTER TABLE workout_sessions DROP CONSTRAINT IF EXISTS chk_status;
+ALTER TABLE workout_sessions ADD CONSTRAINT chk_status CHECK (status IN ('active', 'completed'));
@@ -0,0 +1,15 @@
+ALTER TABLE workout_sessions DROP CONSTRAINT IF EXISTS chk_status;
+ALTER TABLE workout_sessions ADD CONSTRAINT chk_status CHECK (status IN ('active', 'paused', 'completed', 'abandoned'));
+ALTER TABLE workout_sessions ADD COLUMN IF NOT EXISTS abandoned_at TIMESTAMP WITH TIME ZONE;
+
+-- One row per pause of a workout. resumed_at stays NULL while the workout is
+-- paused; a workout has at most one open pause.
+CREATE TABLE IF NOT EXISTS workout_pauses (
+    id SERIAL PRIMARY KEY,
+    workout_session_id INTEGER NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    paused_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resumed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_pauses_workout ON workout_pauses(workout_session_id, paused_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workout_pauses_open ON workout_pauses(workout_session_id) WHERE resumed_at IS NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_workout_sessions_one_unfinished;
@@ -0,0 +1,22 @@
+-- Earlier versions let a user start several workouts at once. Keep the most
+-- recent unfinished one and abandon the rest before enforcing a single one.
+UPDATE workout_sessions ws
+SET status = 'abandoned', abandoned_at = NOW(), updated_at = NOW()
+WHERE ws.status IN ('active', 'paused')
+    AND EXISTS (
+        SELECT 1
+        FROM workout_sessions newer
+        WHERE newer.user_id = ws.user_id
+            AND newer.status IN ('active', 'paused')
+            AND (newer.started_at, newer.id) > (ws.started_at, ws.id)
+    );
+
+UPDATE workout_pauses p
+SET resumed_at = NOW()
+FROM workout_sessions ws
+WHERE p.workout_session_id = ws.id
+    AND ws.status = 'abandoned'
+    AND p.resumed_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workout_sessions_one_unfinished
+    ON workout_sessions(user_id) WHERE status IN ('active', 'paused');
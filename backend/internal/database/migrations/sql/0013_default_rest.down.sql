@@ -0,0 +1,2 @@
+ALTER TABLE exercise_definitions DROP CONSTRAINT IF EXISTS chk_default_rest_seconds;
+ALTER TABLE exercise_definitions DROP COLUMN IF EXISTS default_rest_seconds;
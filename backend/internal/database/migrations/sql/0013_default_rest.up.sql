@@ -0,0 +1,5 @@
+-- Rest to count down after a set of the exercise. NULL falls back to the
+-- application default.
+ALTER TABLE exercise_definitions ADD COLUMN IF NOT EXISTS default_rest_seconds INTEGER;
+ALTER TABLE exercise_definitions ADD CONSTRAINT chk_default_rest_seconds
+    CHECK (default_rest_seconds IS NULL OR (default_rest_seconds >= 0 AND default_rest_seconds <= 3600));
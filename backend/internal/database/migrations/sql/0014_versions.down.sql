@@ -0,0 +1,2 @@
+ALTER TABLE exercises DROP COLUMN IF EXISTS version;
+ALTER TABLE workout_sessions DROP COLUMN IF EXISTS version;
@@ -0,0 +1,5 @@
+-- Incremented on every change so clients can send If-Match and detect edits
+-- made from another device. A workout's version also moves when any of its
+-- exercises or sets change; an exercise's when its sets change.
+ALTER TABLE workout_sessions ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,4 @@
+-- Serves keyset pagination of workout history on (completed_at, id).
+CREATE INDEX IF NOT EXISTS idx_workout_sessions_history
+    ON workout_sessions(user_id, completed_at DESC, id DESC)
+    WHERE status = 'completed';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_exercises_search;
+DROP INDEX IF EXISTS idx_workout_sessions_search;
+ALTER TABLE exercises DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE workout_sessions DROP COLUMN IF EXISTS search_vector;
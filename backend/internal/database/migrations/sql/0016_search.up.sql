@@ -0,0 +1,9 @@
+-- Full-text search over workout and exercise names. The vectors are generated
+-- so they can never drift from the text they index.
+ALTER TABLE workout_sessions ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('english', COALESCE(name, ''))) STORED;
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('english', COALESCE(name, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_workout_sessions_search ON workout_sessions USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_exercises_search ON exercises USING GIN (search_vector);
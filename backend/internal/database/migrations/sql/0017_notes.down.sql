@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS idx_exercises_search;
+DROP INDEX IF EXISTS idx_workout_sessions_search;
+ALTER TABLE exercises DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE workout_sessions DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE workout_sessions ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('english', COALESCE(name, ''))) STORED;
+ALTER TABLE exercises ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('english', COALESCE(name, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_workout_sessions_search ON workout_sessions USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_exercises_search ON exercises USING GIN (search_vector);
+
+ALTER TABLE sets DROP COLUMN IF EXISTS notes;
+ALTER TABLE exercises DROP COLUMN IF EXISTS notes;
+ALTER TABLE workout_sessions DROP COLUMN IF EXISTS notes;
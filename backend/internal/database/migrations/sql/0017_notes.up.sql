@@ -0,0 +1,26 @@
+-- Free-text notes on workouts, exercises and sets. Empty notes are stored as
+-- NULL.
+ALTER TABLE workout_sessions ADD COLUMN IF NOT EXISTS notes TEXT;
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS notes TEXT;
+ALTER TABLE sets ADD COLUMN IF NOT EXISTS notes TEXT;
+
+-- Search notes too, ranked below matches on the name. Generated columns
+-- cannot be altered in place, so the vectors are rebuilt.
+DROP INDEX IF EXISTS idx_exercises_search;
+DROP INDEX IF EXISTS idx_workout_sessions_search;
+ALTER TABLE exercises DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE workout_sessions DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE workout_sessions ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(notes, '')), 'B')
+    ) STORED;
+ALTER TABLE exercises ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(name, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(notes, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_workout_sessions_search ON workout_sessions USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_exercises_search ON exercises USING GIN (search_vector);
@@ -0,0 +1,8 @@
+-- Access tokens revoked before they expire. The cache holds copies of these
+-- lookups; this table is what auth falls back to when the cache cannot say.
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+    jti TEXT PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON revoked_access_tokens(expires_at);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_export_jobs_unfinished;
+
+SELECT lo_unlink(archive_oid) FROM export_jobs WHERE archive_oid IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_export_jobs_expires;
+ALTER TABLE export_jobs DROP COLUMN IF EXISTS archive_oid;
+ALTER TABLE export_jobs ADD COLUMN IF NOT EXISTS file_path TEXT;
+CREATE INDEX IF NOT EXISTS idx_export_jobs_expires ON export_jobs(expires_at) WHERE file_path IS NOT NULL;
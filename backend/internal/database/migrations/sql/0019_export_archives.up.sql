@@ -0,0 +1,21 @@
+-- Export archives move off the API host into Postgres large objects so any
+-- instance can serve a download. Archives already written to local disk
+-- cannot follow and are treated as expired.
+DROP INDEX IF EXISTS idx_export_jobs_expires;
+ALTER TABLE export_jobs DROP COLUMN IF EXISTS file_path;
+ALTER TABLE export_jobs ADD COLUMN IF NOT EXISTS archive_oid OID;
+CREATE INDEX IF NOT EXISTS idx_export_jobs_expires ON export_jobs(expires_at) WHERE archive_oid IS NOT NULL;
+
+-- A user has at most one unfinished job. Older duplicates were left behind
+-- by a restart and are failed before the index is built.
+UPDATE export_jobs
+SET status = 'failed', error = 'export interrupted', completed_at = NOW()
+WHERE status IN ('pending', 'running')
+  AND id NOT IN (
+      SELECT MAX(id)
+      FROM export_jobs
+      WHERE status IN ('pending', 'running')
+      GROUP BY user_id
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_export_jobs_unfinished ON export_jobs(user_id) WHERE status IN ('pending', 'running');
@@ -0,0 +1,4 @@
+ALTER TABLE pr_events DROP COLUMN IF EXISTS new_weight_unit;
+ALTER TABLE pr_events DROP COLUMN IF EXISTS new_weight;
+ALTER TABLE pr_events DROP COLUMN IF EXISTS old_weight_unit;
+ALTER TABLE pr_events DROP COLUMN IF EXISTS old_weight;
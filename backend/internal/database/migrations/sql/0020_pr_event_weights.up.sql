@@ -0,0 +1,15 @@
+-- Heaviest-single and rep-max events keep the weights as they were entered,
+-- so a PR is shown in its own unit rather than rounded back from kilograms.
+ALTER TABLE pr_events ADD COLUMN IF NOT EXISTS old_weight DECIMAL(10, 2);
+ALTER TABLE pr_events ADD COLUMN IF NOT EXISTS old_weight_unit VARCHAR(2);
+ALTER TABLE pr_events ADD COLUMN IF NOT EXISTS new_weight DECIMAL(10, 2);
+ALTER TABLE pr_events ADD COLUMN IF NOT EXISTS new_weight_unit VARCHAR(2);
+
+-- The new side can be recovered from the set that broke the record; the old
+-- side of existing events stays in kilograms.
+UPDATE pr_events p
+SET new_weight = s.weight, new_weight_unit = s.weight_unit
+FROM sets s
+WHERE s.id = p.set_id
+    AND p.category IN ('heaviest_single', 'rep_max')
+    AND s.weight IS NOT NULL;
@@ -1,12 +1,14 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
 
-	_ "github.com/lib/pq"
 	"github.com/damion-14/cadence/backend/internal/config"
+	"github.com/damion-14/cadence/backend/internal/database/migrations"
+	_ "github.com/lib/pq"
 )
 
 func NewPostgresPool(cfg config.DatabaseConfig) (*sql.DB, error) {
@@ -29,5 +31,33 @@ func NewPostgresPool(cfg config.DatabaseConfig) (*sql.DB, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	if cfg.RequireCurrentSchema {
+		if err := checkSchemaVersion(db); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
 	return db, nil
 }
+
+func checkSchemaVersion(db *sql.DB) error {
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	pending, err := migrator.Pending(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("database schema is behind: %d pending migration(s), run `cadence migrate up`", len(pending))
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+const definitionColumns = `id, user_id, name, aliases, primary_muscles, equipment, is_bodyweight, unit_type, default_rest_seconds, created_at, updated_at`
+
+type CatalogQueries struct {
+	db Querier
+}
+
+func NewCatalogQueries(db Querier) *CatalogQueries {
+	return &CatalogQueries{db: db}
+}
+
+// ListDefinitions returns the shared library plus the user's custom definitions.
+func (q *CatalogQueries) ListDefinitions(ctx context.Context, userID int, limit int) ([]models.ExerciseDefinition, error) {
+	query := `
+		SELECT ` + definitionColumns + `
+		FROM exercise_definitions
+		WHERE user_id IS NULL OR user_id = $1
+		ORDER BY name ASC
+		LIMIT $2
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDefinitions(rows)
+}
+
+// SearchDefinitions ranks definitions by trigram similarity against both the
+// canonical name and every alias, so typos and abbreviations still match.
+func (q *CatalogQueries) SearchDefinitions(ctx context.Context, userID int, search string, limit int) ([]models.ExerciseDefinition, error) {
+	query := `
+		SELECT ` + definitionColumns + `
+		FROM (
+			SELECT d.*,
+				GREATEST(
+					similarity(LOWER(d.name), LOWER($2)),
+					COALESCE((SELECT MAX(similarity(a, LOWER($2))) FROM unnest(d.aliases) a), 0),
+					CASE WHEN strpos(LOWER(d.name), LOWER($2)) > 0 THEN 0.5 ELSE 0 END
+				) AS score
+			FROM exercise_definitions d
+			WHERE d.user_id IS NULL OR d.user_id = $1
+		) ranked
+		WHERE score > 0.2
+		ORDER BY score DESC, name ASC
+		LIMIT $3
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, userID, search, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDefinitions(rows)
+}
+
+func (q *CatalogQueries) GetDefinitionByID(ctx context.Context, definitionID int) (*models.ExerciseDefinition, error) {
+	query := `
+		SELECT ` + definitionColumns + `
+		FROM exercise_definitions
+		WHERE id = $1
+	`
+
+	definition, err := scanDefinition(q.db.QueryRowContext(ctx, query, definitionID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("exercise definition not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return definition, nil
+}
+
+// FindDefinitionByName matches a name or alias case-insensitively, preferring
+// the user's own custom definition over the shared library.
+func (q *CatalogQueries) FindDefinitionByName(ctx context.Context, userID int, name string) (*models.ExerciseDefinition, error) {
+	query := `
+		SELECT ` + definitionColumns + `
+		FROM exercise_definitions
+		WHERE (user_id IS NULL OR user_id = $1)
+			AND (LOWER(name) = LOWER($2) OR LOWER($2) = ANY(aliases))
+		ORDER BY user_id NULLS LAST, (LOWER(name) = LOWER($2)) DESC
+		LIMIT 1
+	`
+
+	definition, err := scanDefinition(q.db.QueryRowContext(ctx, query, userID, name))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("exercise definition not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return definition, nil
+}
+
+func (q *CatalogQueries) CreateDefinition(ctx context.Context, userID int, req models.CreateExerciseDefinitionRequest) (*models.ExerciseDefinition, error) {
+	query := `
+		INSERT INTO exercise_definitions (user_id, name, aliases, primary_muscles, equipment, is_bodyweight, unit_type, default_rest_seconds)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + definitionColumns
+
+	return scanDefinition(q.db.QueryRowContext(ctx, query,
+		userID,
+		req.Name,
+		pq.Array(req.Aliases),
+		pq.Array(req.PrimaryMuscles),
+		req.Equipment,
+		req.IsBodyweight,
+		req.UnitType,
+		req.DefaultRestSeconds,
+	))
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDefinition(row rowScanner) (*models.ExerciseDefinition, error) {
+	var definition models.ExerciseDefinition
+	err := row.Scan(
+		&definition.ID,
+		&definition.UserID,
+		&definition.Name,
+		pq.Array(&definition.Aliases),
+		pq.Array(&definition.PrimaryMuscles),
+		&definition.Equipment,
+		&definition.IsBodyweight,
+		&definition.UnitType,
+		&definition.DefaultRestSeconds,
+		&definition.CreatedAt,
+		&definition.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	definition.IsCustom = definition.UserID != nil
+	return &definition, nil
+}
+
+func scanDefinitions(rows *sql.Rows) ([]models.ExerciseDefinition, error) {
+	definitions := []models.ExerciseDefinition{}
+	for rows.Next() {
+		definition, err := scanDefinition(rows)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, *definition)
+	}
+
+	return definitions, rows.Err()
+}
@@ -0,0 +1,391 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+const exportJobColumns = `id, user_id, status, archive_oid, size_bytes, error, created_at, completed_at, expires_at`
+
+type ExportQueries struct {
+	db Querier
+}
+
+func NewExportQueries(db Querier) *ExportQueries {
+	return &ExportQueries{db: db}
+}
+
+// StreamCompletedWorkouts walks the user's completed workouts oldest first
+// and calls fn once per workout with its exercises and sets filled in. Rows
+// are read as they arrive, so only one workout is held in memory at a time.
+// Exercises without sets are kept with an empty Sets; workouts without any
+// exercises are skipped.
+func (q *ExportQueries) StreamCompletedWorkouts(ctx context.Context, userID int, fn func(*models.WorkoutSession) error) error {
+	query := `
+		SELECT
+			ws.id, ws.user_id, ws.name, ws.status, ws.started_at, ws.completed_at, ws.notes, ws.created_at, ws.updated_at,
+			e.id, e.exercise_definition_id, e.name, e.order_index, e.notes, e.created_at, e.updated_at,
+			s.*
+		FROM workout_sessions ws
+		JOIN exercises e ON e.workout_session_id = ws.id
+		LEFT JOIN LATERAL (
+			SELECT ` + setColumns + `
+			FROM sets
+			WHERE exercise_id = e.id
+		) s ON TRUE
+		WHERE ws.user_id = $1 AND ws.status = 'completed'
+		ORDER BY ws.started_at ASC, ws.id ASC, e.order_index ASC, e.id ASC, s.set_number ASC
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var current *models.WorkoutSession
+	for rows.Next() {
+		var workout models.WorkoutSession
+		var exercise models.Exercise
+		var hasSet bool
+
+		set, err := scanSet(nullableScanner{found: &hasSet, row: prefixedScanner{
+			row: rows,
+			prefix: []interface{}{
+				&workout.ID,
+				&workout.UserID,
+				&workout.Name,
+				&workout.Status,
+				&workout.StartedAt,
+				&workout.CompletedAt,
+				&workout.Notes,
+				&workout.CreatedAt,
+				&workout.UpdatedAt,
+				&exercise.ID,
+				&exercise.ExerciseDefinitionID,
+				&exercise.Name,
+				&exercise.OrderIndex,
+				&exercise.Notes,
+				&exercise.CreatedAt,
+				&exercise.UpdatedAt,
+			},
+		}})
+		if err != nil {
+			return err
+		}
+
+		if current == nil || current.ID != workout.ID {
+			if current != nil {
+				if err := fn(current); err != nil {
+					return err
+				}
+			}
+			workout.Exercises = []models.Exercise{}
+			current = &workout
+		}
+
+		last := len(current.Exercises) - 1
+		if last < 0 || current.Exercises[last].ID != exercise.ID {
+			exercise.WorkoutSessionID = workout.ID
+			exercise.Sets = []models.Set{}
+			current.Exercises = append(current.Exercises, exercise)
+			last++
+		}
+		if hasSet {
+			current.Exercises[last].Sets = append(current.Exercises[last].Sets, *set)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if current != nil {
+		return fn(current)
+	}
+	return nil
+}
+
+// prefixedScanner lets a joined row reuse a single-table scan function by
+// scanning the leading joined columns into prefix first.
+type prefixedScanner struct {
+	row    rowScanner
+	prefix []interface{}
+}
+
+func (p prefixedScanner) Scan(dest ...interface{}) error {
+	return p.row.Scan(append(p.prefix, dest...)...)
+}
+
+// nullableScanner lets a single-table scan function read the columns of an
+// outer join, which are all NULL when nothing matched. Each column is read
+// through an extra pointer so NULL leaves dest untouched; found reports
+// whether any column held a value.
+type nullableScanner struct {
+	row   rowScanner
+	found *bool
+}
+
+func (n nullableScanner) Scan(dest ...interface{}) error {
+	holders := make([]interface{}, len(dest))
+	for i := range dest {
+		holders[i] = reflect.New(reflect.TypeOf(dest[i])).Interface()
+	}
+
+	if err := n.row.Scan(holders...); err != nil {
+		return err
+	}
+
+	for i := range holders {
+		value := reflect.ValueOf(holders[i]).Elem()
+		if value.IsNil() {
+			continue
+		}
+		reflect.ValueOf(dest[i]).Elem().Set(value.Elem())
+		*n.found = true
+	}
+	return nil
+}
+
+// CreateExportJob queues a job for the user, or returns nil when the user
+// already has one unfinished; the unique index on unfinished jobs settles
+// concurrent requests.
+func (q *ExportQueries) CreateExportJob(ctx context.Context, userID int) (*models.ExportJob, error) {
+	query := `
+		INSERT INTO export_jobs (user_id)
+		VALUES ($1)
+		ON CONFLICT (user_id) WHERE status IN ('pending', 'running') DO NOTHING
+		RETURNING ` + exportJobColumns
+
+	job, err := scanExportJob(q.db.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+func (q *ExportQueries) GetExportJob(ctx context.Context, jobID int) (*models.ExportJob, error) {
+	query := `
+		SELECT ` + exportJobColumns + `
+		FROM export_jobs
+		WHERE id = $1
+	`
+
+	job, err := scanExportJob(q.db.QueryRowContext(ctx, query, jobID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("export job not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// GetUnfinishedExportJob returns the user's pending or running job, if any.
+func (q *ExportQueries) GetUnfinishedExportJob(ctx context.Context, userID int) (*models.ExportJob, error) {
+	query := `
+		SELECT ` + exportJobColumns + `
+		FROM export_jobs
+		WHERE user_id = $1 AND status IN ('pending', 'running')
+	`
+
+	job, err := scanExportJob(q.db.QueryRowContext(ctx, query, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
+
+// FailStaleExportJobs fails the user's unfinished jobs created before
+// before. They were cut off by a restart and would otherwise block a new
+// job forever.
+func (q *ExportQueries) FailStaleExportJobs(ctx context.Context, userID int, before time.Time) error {
+	query := `
+		UPDATE export_jobs
+		SET status = 'failed', error = 'export interrupted', completed_at = NOW()
+		WHERE user_id = $1 AND status IN ('pending', 'running') AND created_at < $2
+	`
+
+	_, err := q.db.ExecContext(ctx, query, userID, before)
+	return err
+}
+
+func (q *ExportQueries) MarkExportJobRunning(ctx context.Context, jobID int) error {
+	query := `
+		UPDATE export_jobs
+		SET status = 'running'
+		WHERE id = $1
+	`
+
+	_, err := q.db.ExecContext(ctx, query, jobID)
+	return err
+}
+
+func (q *ExportQueries) CompleteExportJob(ctx context.Context, jobID int, archiveOID int64, sizeBytes int64, expiresAt time.Time) error {
+	query := `
+		UPDATE export_jobs
+		SET status = 'completed', archive_oid = $1, size_bytes = $2, completed_at = NOW(), expires_at = $3
+		WHERE id = $4
+	`
+
+	_, err := q.db.ExecContext(ctx, query, archiveOID, sizeBytes, expiresAt, jobID)
+	return err
+}
+
+func (q *ExportQueries) FailExportJob(ctx context.Context, jobID int, message string) error {
+	query := `
+		UPDATE export_jobs
+		SET status = 'failed', error = $1, completed_at = NOW()
+		WHERE id = $2
+	`
+
+	_, err := q.db.ExecContext(ctx, query, message, jobID)
+	return err
+}
+
+// ClearExpiredExportArchives detaches archives past their expiry from their
+// jobs and deletes them.
+func (q *ExportQueries) ClearExpiredExportArchives(ctx context.Context) error {
+	query := `
+		WITH expired AS (
+			SELECT id, archive_oid
+			FROM export_jobs
+			WHERE archive_oid IS NOT NULL AND expires_at < NOW()
+			FOR UPDATE
+		), cleared AS (
+			UPDATE export_jobs ej
+			SET archive_oid = NULL
+			FROM expired
+			WHERE ej.id = expired.id
+			RETURNING expired.archive_oid
+		)
+		SELECT lo_unlink(archive_oid) FROM cleared
+	`
+
+	_, err := q.db.ExecContext(ctx, query)
+	return err
+}
+
+// archiveChunkSize bounds how much of an archive moves in one round trip.
+const archiveChunkSize = 1 << 20
+
+// WriteExportArchive copies r into a new large object and returns its OID
+// and size. Run it in the transaction that completes the job, so a failed
+// copy leaves no orphaned object behind.
+func (q *ExportQueries) WriteExportArchive(ctx context.Context, r io.Reader) (int64, int64, error) {
+	var oid int64
+	if err := q.db.QueryRowContext(ctx, `SELECT lo_create(0)`).Scan(&oid); err != nil {
+		return 0, 0, err
+	}
+
+	buf := make([]byte, archiveChunkSize)
+	var size int64
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			if _, err := q.db.ExecContext(ctx, `SELECT lo_put($1, $2, $3)`, oid, size, buf[:n]); err != nil {
+				return 0, 0, err
+			}
+			size += int64(n)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return oid, size, nil
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+}
+
+// OpenExportArchive returns a reader over a stored archive of size bytes.
+func (q *ExportQueries) OpenExportArchive(ctx context.Context, oid, size int64) *ArchiveReader {
+	return &ArchiveReader{db: q.db, ctx: ctx, oid: oid, size: size}
+}
+
+// ArchiveReader reads a stored archive a chunk at a time, so serving a
+// download never holds the whole archive in memory. It seeks for range
+// requests.
+type ArchiveReader struct {
+	db     Querier
+	ctx    context.Context
+	oid    int64
+	size   int64
+	offset int64
+}
+
+func (a *ArchiveReader) Read(p []byte) (int, error) {
+	if a.offset >= a.size {
+		return 0, io.EOF
+	}
+
+	length := min(int64(len(p)), a.size-a.offset, archiveChunkSize)
+
+	var chunk []byte
+	err := a.db.QueryRowContext(a.ctx, `SELECT lo_get($1, $2, $3)`, a.oid, a.offset, length).Scan(&chunk)
+	if err != nil {
+		return 0, err
+	}
+	if len(chunk) == 0 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	n := copy(p, chunk)
+	a.offset += int64(n)
+	return n, nil
+}
+
+func (a *ArchiveReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += a.offset
+	case io.SeekEnd:
+		offset += a.size
+	default:
+		return 0, fmt.Errorf("invalid whence %d", whence)
+	}
+
+	if offset < 0 {
+		return 0, fmt.Errorf("negative position %d", offset)
+	}
+
+	a.offset = offset
+	return offset, nil
+}
+
+func scanExportJob(row rowScanner) (*models.ExportJob, error) {
+	var job models.ExportJob
+	err := row.Scan(
+		&job.ID,
+		&job.UserID,
+		&job.Status,
+		&job.ArchiveOID,
+		&job.SizeBytes,
+		&job.Error,
+		&job.CreatedAt,
+		&job.CompletedAt,
+		&job.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
@@ -0,0 +1,74 @@
+package queries
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+// CreateRevision stores an edit to a completed workout with snapshots of the
+// workout before and after it.
+func (q *WorkoutQueries) CreateRevision(ctx context.Context, userID int, action string, before, after *models.WorkoutSession) error {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO workout_revisions (workout_session_id, user_id, action, before, after)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err = q.db.ExecContext(ctx, query, after.ID, userID, action, beforeJSON, afterJSON)
+	return err
+}
+
+// ListRevisions returns the workout's revisions, newest first.
+func (q *WorkoutQueries) ListRevisions(ctx context.Context, workoutID int) ([]models.WorkoutRevision, error) {
+	query := `
+		SELECT id, workout_session_id, user_id, action, before, after, created_at
+		FROM workout_revisions
+		WHERE workout_session_id = $1
+		ORDER BY created_at DESC, id DESC
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.WorkoutRevision{}
+	for rows.Next() {
+		var revision models.WorkoutRevision
+		var beforeJSON, afterJSON []byte
+		if err := rows.Scan(
+			&revision.ID,
+			&revision.WorkoutSessionID,
+			&revision.UserID,
+			&revision.Action,
+			&beforeJSON,
+			&afterJSON,
+			&revision.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(beforeJSON, &revision.Before); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(afterJSON, &revision.After); err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, revision)
+	}
+
+	return revisions, rows.Err()
+}
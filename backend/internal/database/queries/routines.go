@@ -0,0 +1,258 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+type RoutineQueries struct {
+	db Querier
+}
+
+func NewRoutineQueries(db Querier) *RoutineQueries {
+	return &RoutineQueries{db: db}
+}
+
+func (q *RoutineQueries) CreateRoutine(ctx context.Context, userID int, name string) (*models.Routine, error) {
+	query := `
+		INSERT INTO routines (user_id, name)
+		VALUES ($1, $2)
+		RETURNING id, user_id, name, created_at, updated_at
+	`
+
+	var routine models.Routine
+	err := q.db.QueryRowContext(ctx, query, userID, name).Scan(
+		&routine.ID,
+		&routine.UserID,
+		&routine.Name,
+		&routine.CreatedAt,
+		&routine.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	routine.Exercises = []models.RoutineExercise{}
+	return &routine, nil
+}
+
+func (q *RoutineQueries) GetRoutineByID(ctx context.Context, routineID int) (*models.Routine, error) {
+	query := `
+		SELECT id, user_id, name, created_at, updated_at
+		FROM routines
+		WHERE id = $1
+	`
+
+	var routine models.Routine
+	err := q.db.QueryRowContext(ctx, query, routineID).Scan(
+		&routine.ID,
+		&routine.UserID,
+		&routine.Name,
+		&routine.CreatedAt,
+		&routine.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("routine not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	exercises, err := q.getRoutineExercises(ctx, []int{routine.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	routine.Exercises = exercises[routine.ID]
+	if routine.Exercises == nil {
+		routine.Exercises = []models.RoutineExercise{}
+	}
+
+	return &routine, nil
+}
+
+func (q *RoutineQueries) GetRoutinesByUserID(ctx context.Context, userID int) ([]models.Routine, error) {
+	query := `
+		SELECT id, user_id, name, created_at, updated_at
+		FROM routines
+		WHERE user_id = $1
+		ORDER BY name ASC, id ASC
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	routines := []models.Routine{}
+	routineIDs := []int{}
+	for rows.Next() {
+		var routine models.Routine
+		err := rows.Scan(
+			&routine.ID,
+			&routine.UserID,
+			&routine.Name,
+			&routine.CreatedAt,
+			&routine.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		routines = append(routines, routine)
+		routineIDs = append(routineIDs, routine.ID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(routines) == 0 {
+		return routines, nil
+	}
+
+	exercises, err := q.getRoutineExercises(ctx, routineIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range routines {
+		routines[i].Exercises = exercises[routines[i].ID]
+		if routines[i].Exercises == nil {
+			routines[i].Exercises = []models.RoutineExercise{}
+		}
+	}
+
+	return routines, nil
+}
+
+func (q *RoutineQueries) UpdateRoutineName(ctx context.Context, routineID int, name string) (*models.Routine, error) {
+	query := `
+		UPDATE routines
+		SET name = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING id, user_id, name, created_at, updated_at
+	`
+
+	var routine models.Routine
+	err := q.db.QueryRowContext(ctx, query, name, routineID).Scan(
+		&routine.ID,
+		&routine.UserID,
+		&routine.Name,
+		&routine.CreatedAt,
+		&routine.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("routine not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	routine.Exercises = []models.RoutineExercise{}
+	return &routine, nil
+}
+
+func (q *RoutineQueries) DeleteRoutine(ctx context.Context, routineID int) error {
+	query := `DELETE FROM routines WHERE id = $1`
+
+	result, err := q.db.ExecContext(ctx, query, routineID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("routine not found")
+	}
+
+	return nil
+}
+
+func (q *RoutineQueries) getRoutineExercises(ctx context.Context, routineIDs []int) (map[int][]models.RoutineExercise, error) {
+	query := `
+		SELECT id, routine_id, exercise_definition_id, name, order_index, target_sets, target_reps, target_weight, weight_unit, is_bodyweight, created_at, updated_at
+		FROM routine_exercises
+		WHERE routine_id = ANY($1)
+		ORDER BY routine_id ASC, order_index ASC
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, pq.Array(routineIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exercises := map[int][]models.RoutineExercise{}
+	for rows.Next() {
+		var exercise models.RoutineExercise
+		err := rows.Scan(
+			&exercise.ID,
+			&exercise.RoutineID,
+			&exercise.ExerciseDefinitionID,
+			&exercise.Name,
+			&exercise.OrderIndex,
+			&exercise.TargetSets,
+			&exercise.TargetReps,
+			&exercise.TargetWeight,
+			&exercise.WeightUnit,
+			&exercise.IsBodyweight,
+			&exercise.CreatedAt,
+			&exercise.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		exercises[exercise.RoutineID] = append(exercises[exercise.RoutineID], exercise)
+	}
+
+	return exercises, rows.Err()
+}
+
+func (q *RoutineQueries) CreateRoutineExercise(ctx context.Context, routineID, orderIndex int, input models.RoutineExerciseInput) (*models.RoutineExercise, error) {
+	query := `
+		INSERT INTO routine_exercises (routine_id, exercise_definition_id, name, order_index, target_sets, target_reps, target_weight, weight_unit, is_bodyweight)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, routine_id, exercise_definition_id, name, order_index, target_sets, target_reps, target_weight, weight_unit, is_bodyweight, created_at, updated_at
+	`
+
+	var exercise models.RoutineExercise
+	err := q.db.QueryRowContext(ctx, query, routineID, input.ExerciseDefinitionID, input.Name, orderIndex, input.TargetSets, input.TargetReps, input.TargetWeight, input.WeightUnit, input.IsBodyweight).Scan(
+		&exercise.ID,
+		&exercise.RoutineID,
+		&exercise.ExerciseDefinitionID,
+		&exercise.Name,
+		&exercise.OrderIndex,
+		&exercise.TargetSets,
+		&exercise.TargetReps,
+		&exercise.TargetWeight,
+		&exercise.WeightUnit,
+		&exercise.IsBodyweight,
+		&exercise.CreatedAt,
+		&exercise.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exercise, nil
+}
+
+func (q *RoutineQueries) DeleteRoutineExercises(ctx context.Context, routineID int) error {
+	query := `DELETE FROM routine_exercises WHERE routine_id = $1`
+
+	_, err := q.db.ExecContext(ctx, query, routineID)
+	return err
+}
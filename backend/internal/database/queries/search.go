@@ -0,0 +1,98 @@
+package queries
+
+import (
+	"context"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+type SearchQueries struct {
+	db Querier
+}
+
+func NewSearchQueries(db Querier) *SearchQueries {
+	return &SearchQueries{db: db}
+}
+
+// Search matches search, in web search syntax, against the names and notes
+// of the user's workouts and exercises. An exercise's set notes are searched
+// along with it, so one query can match its name and a set's cue. Hits are
+// ranked by relevance, with name matches above note matches and set notes
+// last, then by how recently the workout started. Abandoned workouts are
+// left out.
+func (q *SearchQueries) Search(ctx context.Context, userID int, search string, limit, offset int) ([]models.SearchHit, error) {
+	query := `
+		WITH search AS (
+			SELECT websearch_to_tsquery('english', $2) AS query
+		)
+		SELECT type, rank, snippet, exercise_id, exercise_name,
+			workout_id, workout_name, status, started_at, completed_at
+		FROM (
+			SELECT
+				'workout' AS type,
+				ts_rank(ws.search_vector, search.query) AS rank,
+				ts_headline('english', concat_ws(' - ', ws.name, ws.notes), search.query, 'StartSel=**, StopSel=**, HighlightAll=true') AS snippet,
+				NULL::INTEGER AS exercise_id,
+				NULL::TEXT AS exercise_name,
+				ws.id AS workout_id, COALESCE(ws.name, '') AS workout_name, ws.status, ws.started_at, ws.completed_at
+			FROM workout_sessions ws, search
+			WHERE ws.user_id = $1
+				AND ws.status <> 'abandoned'
+				AND ws.search_vector @@ search.query
+
+			UNION ALL
+
+			SELECT
+				'exercise',
+				ts_rank(e.search_vector || set_notes.vector, search.query),
+				ts_headline('english', concat_ws(' - ', e.name, e.notes, set_notes.notes), search.query, 'StartSel=**, StopSel=**, HighlightAll=true'),
+				e.id,
+				e.name,
+				ws.id, COALESCE(ws.name, ''), ws.status, ws.started_at, ws.completed_at
+			FROM exercises e
+			JOIN workout_sessions ws ON ws.id = e.workout_session_id
+			CROSS JOIN search
+			CROSS JOIN LATERAL (
+				SELECT
+					string_agg(s.notes, ' - ' ORDER BY s.set_number, s.id) AS notes,
+					setweight(to_tsvector('english', COALESCE(string_agg(s.notes, ' '), '')), 'C') AS vector
+				FROM sets s
+				WHERE s.exercise_id = e.id AND s.notes IS NOT NULL
+			) set_notes
+			WHERE ws.user_id = $1
+				AND ws.status <> 'abandoned'
+				AND (e.search_vector || set_notes.vector) @@ search.query
+		) hits
+		ORDER BY rank DESC, started_at DESC, workout_id DESC, exercise_id ASC NULLS FIRST
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, userID, search, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := []models.SearchHit{}
+	for rows.Next() {
+		var hit models.SearchHit
+		err := rows.Scan(
+			&hit.Type,
+			&hit.Rank,
+			&hit.Snippet,
+			&hit.ExerciseID,
+			&hit.ExerciseName,
+			&hit.Workout.ID,
+			&hit.Workout.Name,
+			&hit.Workout.Status,
+			&hit.Workout.StartedAt,
+			&hit.Workout.CompletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		hits = append(hits, hit)
+	}
+
+	return hits, rows.Err()
+}
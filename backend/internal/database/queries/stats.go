@@ -2,57 +2,150 @@ package queries
 
 import (
 	"context"
-	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/lib/pq"
 )
 
 type StatsQueries struct {
-	db *sql.DB
+	db Querier
 }
 
-func NewStatsQueries(db *sql.DB) *StatsQueries {
+func NewStatsQueries(db Querier) *StatsQueries {
 	return &StatsQueries{db: db}
 }
 
-func (q *StatsQueries) GetPersonalRecords(ctx context.Context, userID int) ([]models.PersonalRecord, error) {
+// e1RMFormulas holds the SQL for each estimated one-rep max formula, with
+// the weight and reps columns substituted in by e1RMExpression. Callers must
+// validate the formula name first.
+var e1RMFormulas = map[string]string{
+	models.E1RMEpley:    `%[1]s * (1 + %[2]s / 30.0)`,
+	models.E1RMBrzycki:  `%[1]s * 36.0 / (37 - LEAST(%[2]s, 36))`,
+	models.E1RMLombardi: `%[1]s * POWER(%[2]s, 0.10)`,
+}
+
+func e1RMExpression(formula, weight, reps string) string {
+	return fmt.Sprintf(`ROUND(CASE WHEN %[2]s = 1 THEN %[1]s ELSE `+e1RMFormulas[formula]+` END, 1)`, weight, reps)
+}
+
+// Warm-up sets and sets not marked completed are excluded from every PR and
+// volume figure below. Figures are returned in kilograms, except set weights,
+// which come back in the unit they were entered in; handlers convert both to
+// the display unit.
+//
+// GetPersonalRecords returns each exercise's best set per category. Ties go
+// to the earliest workout, since that is when the record was first set.
+func (q *StatsQueries) GetPersonalRecords(ctx context.Context, userID int, formula string) ([]models.PersonalRecord, error) {
+	return q.getRecords(ctx, userID, formula, "", nil)
+}
+
+// GetRecordsBeforeWorkout returns the records that stood before the given
+// workout was completed, ignoring that workout and anything finished later.
+func (q *StatsQueries) GetRecordsBeforeWorkout(ctx context.Context, userID, workoutID int, formula string) ([]models.PersonalRecord, error) {
+	filter := `AND ws.id <> $3 AND ws.completed_at < (SELECT completed_at FROM workout_sessions WHERE id = $3)`
+	return q.getRecords(ctx, userID, formula, filter, []interface{}{workoutID})
+}
+
+// GetWorkoutRecords returns the best sets of a single completed workout in
+// the same shape as GetPersonalRecords.
+func (q *StatsQueries) GetWorkoutRecords(ctx context.Context, userID, workoutID int, formula string) ([]models.PersonalRecord, error) {
+	return q.getRecords(ctx, userID, formula, `AND ws.id = $3`, []interface{}{workoutID})
+}
+
+// getRecords narrows the working sets with filter, which may refer to extra
+// args as $3 onwards.
+func (q *StatsQueries) getRecords(ctx context.Context, userID int, formula string, filter string, args []interface{}) ([]models.PersonalRecord, error) {
 	query := `
-		WITH exercise_prs AS (
+		WITH working_sets AS (
 			SELECT
-				e.name AS exercise_name,
-				MAX(s.weight) AS max_weight,
-				MAX(s.reps) AS max_reps,
-				MAX(COALESCE(s.weight, 0) * s.reps) AS max_volume,
+				e.exercise_definition_id,
+				s.id AS set_id,
+				s.reps,
+				s.weight_kg AS weight,
+				s.weight AS entered_weight,
+				s.weight_unit,
+				ws.id AS workout_id,
+				ws.name AS workout_name,
 				ws.completed_at
-			FROM exercises e
+			FROM sets s
+			JOIN exercises e ON s.exercise_id = e.id
 			JOIN workout_sessions ws ON e.workout_session_id = ws.id
-			JOIN sets s ON s.exercise_id = e.id
-			WHERE ws.user_id = $1 AND ws.status = 'completed'
-			GROUP BY e.name, ws.completed_at
+			WHERE ws.user_id = $1
+				AND ws.status = 'completed'
+				AND s.is_completed
+				AND s.set_type <> 'warmup'
+				` + filter + `
 		),
-		ranked_prs AS (
-			SELECT
-				exercise_name,
-				max_weight,
-				max_reps,
-				max_volume,
-				completed_at,
-				ROW_NUMBER() OVER (PARTITION BY exercise_name ORDER BY max_volume DESC, completed_at DESC) as rn
-			FROM exercise_prs
+		records AS (
+			(
+				SELECT DISTINCT ON (exercise_definition_id)
+					exercise_definition_id, 'heaviest_single' AS category, NULL::INTEGER AS rep_count, weight AS value,
+					set_id, reps, entered_weight, weight_unit, workout_id, workout_name, completed_at
+				FROM working_sets
+				WHERE weight > 0
+				ORDER BY exercise_definition_id, weight DESC, reps DESC, completed_at ASC
+			)
+			UNION ALL
+			(
+				SELECT DISTINCT ON (exercise_definition_id, reps)
+					exercise_definition_id, 'rep_max', reps, weight,
+					set_id, reps, entered_weight, weight_unit, workout_id, workout_name, completed_at
+				FROM working_sets
+				WHERE weight > 0 AND reps <= $2
+				ORDER BY exercise_definition_id, reps, weight DESC, completed_at ASC
+			)
+			UNION ALL
+			(
+				SELECT DISTINCT ON (exercise_definition_id)
+					exercise_definition_id, 'e1rm', NULL, ` + e1RMExpression(formula, "weight", "reps") + `,
+					set_id, reps, entered_weight, weight_unit, workout_id, workout_name, completed_at
+				FROM working_sets
+				WHERE weight > 0
+				ORDER BY exercise_definition_id, ` + e1RMExpression(formula, "weight", "reps") + ` DESC, completed_at ASC
+			)
+			UNION ALL
+			(
+				SELECT DISTINCT ON (exercise_definition_id)
+					exercise_definition_id, 'most_reps', NULL, reps,
+					set_id, reps, entered_weight, weight_unit, workout_id, workout_name, completed_at
+				FROM working_sets
+				ORDER BY exercise_definition_id, reps DESC, weight DESC NULLS LAST, completed_at ASC
+			)
+			UNION ALL
+			(
+				SELECT DISTINCT ON (exercise_definition_id)
+					exercise_definition_id, 'session_volume', NULL, volume,
+					NULL::INTEGER, NULL::INTEGER, NULL::DECIMAL, NULL::VARCHAR, workout_id, workout_name, completed_at
+				FROM (
+					SELECT exercise_definition_id, workout_id, workout_name, completed_at, SUM(COALESCE(weight, 0) * reps) AS volume
+					FROM working_sets
+					GROUP BY exercise_definition_id, workout_id, workout_name, completed_at
+				) sessions
+				WHERE volume > 0
+				ORDER BY exercise_definition_id, volume DESC, completed_at ASC
+			)
 		)
 		SELECT
-			exercise_name,
-			max_weight,
-			max_reps,
-			max_volume,
-			completed_at
-		FROM ranked_prs
-		WHERE rn = 1
-		ORDER BY exercise_name ASC
+			r.exercise_definition_id,
+			d.name,
+			r.category,
+			r.rep_count,
+			r.value,
+			r.set_id,
+			r.reps,
+			r.entered_weight,
+			COALESCE(r.weight_unit, ''),
+			r.workout_id,
+			r.workout_name,
+			r.completed_at
+		FROM records r
+		JOIN exercise_definitions d ON d.id = r.exercise_definition_id
+		ORDER BY d.name ASC, r.exercise_definition_id ASC, r.rep_count ASC NULLS FIRST
 	`
 
-	rows, err := q.db.QueryContext(ctx, query, userID)
+	rows, err := q.db.QueryContext(ctx, query, append([]interface{}{userID, models.MaxRepMax}, args...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -60,56 +153,207 @@ func (q *StatsQueries) GetPersonalRecords(ctx context.Context, userID int) ([]mo
 
 	prs := []models.PersonalRecord{}
 	for rows.Next() {
-		var pr models.PersonalRecord
+		var definitionID int
+		var name string
+		var record models.Record
 		err := rows.Scan(
-			&pr.ExerciseName,
-			&pr.MaxWeight,
-			&pr.MaxReps,
-			&pr.MaxVolume,
-			&pr.AchievedAt,
+			&definitionID,
+			&name,
+			&record.Category,
+			&record.RepCount,
+			&record.Value,
+			&record.SetID,
+			&record.Reps,
+			&record.Weight,
+			&record.WeightUnit,
+			&record.WorkoutID,
+			&record.WorkoutName,
+			&record.AchievedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
-		prs = append(prs, pr)
+
+		if len(prs) == 0 || prs[len(prs)-1].ExerciseDefinitionID != definitionID {
+			prs = append(prs, models.PersonalRecord{
+				ExerciseDefinitionID: definitionID,
+				ExerciseName:         name,
+				Formula:              formula,
+				RepMaxes:             []models.Record{},
+			})
+		}
+
+		pr := &prs[len(prs)-1]
+		switch record.Category {
+		case models.PRCategoryHeaviestSingle:
+			pr.HeaviestSingle = &record
+		case models.PRCategoryRepMax:
+			pr.RepMaxes = append(pr.RepMaxes, record)
+		case models.PRCategoryE1RM:
+			pr.BestE1RM = &record
+		case models.PRCategoryMostReps:
+			pr.MostReps = &record
+		case models.PRCategorySessionVolume:
+			pr.BestSessionVolume = &record
+		}
 	}
 
-	return prs, nil
+	return prs, rows.Err()
 }
 
-func (q *StatsQueries) GetWorkoutHistory(ctx context.Context, userID int, limit, offset int) ([]models.WorkoutSummary, int, error) {
+func (q *StatsQueries) CreatePREvent(ctx context.Context, userID int, event *models.PREvent) error {
+	query := `
+		INSERT INTO pr_events (
+			user_id, workout_session_id, exercise_definition_id, set_id, category, rep_count, formula,
+			old_value, new_value, old_weight, old_weight_unit, new_weight, new_weight_unit, achieved_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING id
+	`
+
+	return q.db.QueryRowContext(ctx, query,
+		userID,
+		event.WorkoutSessionID,
+		event.ExerciseDefinitionID,
+		event.SetID,
+		event.Category,
+		event.RepCount,
+		event.Formula,
+		event.OldValue,
+		event.NewValue,
+		event.OldWeight,
+		event.OldWeightUnit,
+		event.NewWeight,
+		event.NewWeightUnit,
+		event.AchievedAt,
+	).Scan(&event.ID)
+}
+
+// GetPRTimeline lists PR events newest first, optionally for one exercise.
+func (q *StatsQueries) GetPRTimeline(ctx context.Context, userID int, definitionID *int, limit, offset int) ([]models.PREvent, int, error) {
 	countQuery := `
 		SELECT COUNT(*)
-		FROM workout_sessions
-		WHERE user_id = $1 AND status = 'completed'
+		FROM pr_events
+		WHERE user_id = $1 AND ($2::INTEGER IS NULL OR exercise_definition_id = $2)
 	`
 
 	var total int
-	err := q.db.QueryRowContext(ctx, countQuery, userID).Scan(&total)
+	if err := q.db.QueryRowContext(ctx, countQuery, userID, definitionID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT
+			p.id,
+			p.workout_session_id,
+			p.exercise_definition_id,
+			d.name,
+			p.set_id,
+			p.category,
+			p.rep_count,
+			p.formula,
+			p.old_value,
+			p.new_value,
+			p.old_weight,
+			p.old_weight_unit,
+			p.new_weight,
+			p.new_weight_unit,
+			p.achieved_at
+		FROM pr_events p
+		JOIN exercise_definitions d ON d.id = p.exercise_definition_id
+		WHERE p.user_id = $1 AND ($2::INTEGER IS NULL OR p.exercise_definition_id = $2)
+		ORDER BY p.achieved_at DESC, p.id ASC
+		LIMIT $3 OFFSET $4
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, userID, definitionID, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
+	defer rows.Close()
+
+	events := []models.PREvent{}
+	for rows.Next() {
+		var event models.PREvent
+		err := rows.Scan(
+			&event.ID,
+			&event.WorkoutSessionID,
+			&event.ExerciseDefinitionID,
+			&event.ExerciseName,
+			&event.SetID,
+			&event.Category,
+			&event.RepCount,
+			&event.Formula,
+			&event.OldValue,
+			&event.NewValue,
+			&event.OldWeight,
+			&event.OldWeightUnit,
+			&event.NewWeight,
+			&event.NewWeightUnit,
+			&event.AchievedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		events = append(events, event)
+	}
+
+	return events, total, rows.Err()
+}
+
+// GetWorkoutHistory returns up to limit completed workouts matching filter,
+// newest first, starting after cursor in (completed_at, id) order. Paging by
+// key rather than offset means workouts completed between page loads never
+// shift later pages.
+func (q *StatsQueries) GetWorkoutHistory(ctx context.Context, userID int, filter models.HistoryFilter, cursor *models.HistoryCursor, limit int) ([]models.WorkoutSummary, error) {
+	var cursorCompletedAt *time.Time
+	var cursorID *int
+	if cursor != nil {
+		cursorCompletedAt = &cursor.CompletedAt
+		cursorID = &cursor.ID
+	}
 
 	query := `
 		SELECT
 			ws.id,
 			ws.name,
 			ws.completed_at,
+			ws.notes,
 			COUNT(DISTINCT e.id) AS exercise_count,
 			COUNT(s.id) AS total_sets,
-			COALESCE(SUM(COALESCE(s.weight, 0) * s.reps), 0) AS total_volume
+			COALESCE(SUM(COALESCE(s.weight_kg, 0) * s.reps) FILTER (WHERE s.set_type <> 'warmup'), 0) AS total_volume
 		FROM workout_sessions ws
 		LEFT JOIN exercises e ON e.workout_session_id = ws.id
-		LEFT JOIN sets s ON s.exercise_id = e.id
+		LEFT JOIN sets s ON s.exercise_id = e.id AND s.is_completed
 		WHERE ws.user_id = $1 AND ws.status = 'completed'
-		GROUP BY ws.id, ws.name, ws.completed_at
-		ORDER BY ws.completed_at DESC
-		LIMIT $2 OFFSET $3
+			AND ($2::TIMESTAMPTZ IS NULL OR ws.completed_at >= $2)
+			AND ($3::TIMESTAMPTZ IS NULL OR ws.completed_at < $3)
+			AND ($4 = '' OR strpos(lower(ws.name), lower($4)) > 0)
+			AND ($5::INTEGER IS NULL OR EXISTS (
+				SELECT 1 FROM exercises fe
+				WHERE fe.workout_session_id = ws.id AND fe.exercise_definition_id = $5
+			))
+			AND ($6::TIMESTAMPTZ IS NULL OR (ws.completed_at, ws.id) < ($6, $7::INTEGER))
+		GROUP BY ws.id, ws.name, ws.completed_at, ws.notes
+		HAVING $8::FLOAT8 IS NULL
+			OR COALESCE(SUM(COALESCE(s.weight_kg, 0) * s.reps) FILTER (WHERE s.set_type <> 'warmup'), 0) >= $8
+		ORDER BY ws.completed_at DESC, ws.id DESC
+		LIMIT $9
 	`
 
-	rows, err := q.db.QueryContext(ctx, query, userID, limit, offset)
+	rows, err := q.db.QueryContext(ctx, query,
+		userID,
+		filter.From,
+		filter.To,
+		filter.Name,
+		filter.ExerciseDefinitionID,
+		cursorCompletedAt,
+		cursorID,
+		filter.MinVolume,
+		limit,
+	)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -120,30 +364,33 @@ func (q *StatsQueries) GetWorkoutHistory(ctx context.Context, userID int, limit,
 			&workout.ID,
 			&workout.Name,
 			&workout.CompletedAt,
+			&workout.Notes,
 			&workout.ExerciseCount,
 			&workout.TotalSets,
 			&workout.TotalVolume,
 		)
 		if err != nil {
-			return nil, 0, err
+			return nil, err
 		}
 		workouts = append(workouts, workout)
 	}
 
-	return workouts, total, nil
+	return workouts, rows.Err()
 }
 
-func (q *StatsQueries) GetWeeklySummary(ctx context.Context, userID int, year int, week int) (*models.WeeklySummary, error) {
-	startOfWeek, endOfWeek := getWeekBounds(year, week)
+// GetWeeklySummary reports ISO week year-Wweek as observed in loc, so a late
+// Sunday workout counts toward the week the user saw it in.
+func (q *StatsQueries) GetWeeklySummary(ctx context.Context, userID int, year int, week int, loc *time.Location) (*models.WeeklySummary, error) {
+	startOfWeek, endOfWeek := getWeekBounds(year, week, loc)
 
 	summaryQuery := `
 		SELECT
 			COUNT(DISTINCT ws.id) AS total_workouts,
 			COUNT(DISTINCT e.id) AS total_exercises,
-			COALESCE(SUM(COALESCE(s.weight, 0) * s.reps), 0) AS total_volume
+			COALESCE(SUM(COALESCE(s.weight_kg, 0) * s.reps) FILTER (WHERE s.set_type <> 'warmup'), 0) AS total_volume
 		FROM workout_sessions ws
 		LEFT JOIN exercises e ON e.workout_session_id = ws.id
-		LEFT JOIN sets s ON s.exercise_id = e.id
+		LEFT JOIN sets s ON s.exercise_id = e.id AND s.is_completed
 		WHERE ws.user_id = $1
 			AND ws.status = 'completed'
 			AND ws.completed_at >= $2
@@ -165,7 +412,7 @@ func (q *StatsQueries) GetWeeklySummary(ctx context.Context, userID int, year in
 			ws.id,
 			ws.name,
 			ws.completed_at,
-			EXTRACT(DOW FROM ws.completed_at)::INTEGER AS day_of_week
+			EXTRACT(DOW FROM ws.completed_at AT TIME ZONE $4)::INTEGER AS day_of_week
 		FROM workout_sessions ws
 		WHERE ws.user_id = $1
 			AND ws.status = 'completed'
@@ -174,7 +421,7 @@ func (q *StatsQueries) GetWeeklySummary(ctx context.Context, userID int, year in
 		ORDER BY ws.completed_at ASC
 	`
 
-	rows, err := q.db.QueryContext(ctx, workoutsQuery, userID, startOfWeek, endOfWeek)
+	rows, err := q.db.QueryContext(ctx, workoutsQuery, userID, startOfWeek, endOfWeek, loc.String())
 	if err != nil {
 		return nil, err
 	}
@@ -201,27 +448,36 @@ func (q *StatsQueries) GetWeeklySummary(ctx context.Context, userID int, year in
 	return &summary, nil
 }
 
-func (q *StatsQueries) GetExerciseProgress(ctx context.Context, userID int, exerciseName string, days int) ([]models.ProgressDataPoint, error) {
+// GetExerciseProgress returns one data point per calendar day in loc, with
+// the day's heaviest set as entered. Warm-up sets count toward neither the
+// numbers nor the notes.
+func (q *StatsQueries) GetExerciseProgress(ctx context.Context, userID int, definitionID int, days int, formula string, loc *time.Location) ([]models.ProgressDataPoint, error) {
 	cutoffDate := time.Now().AddDate(0, 0, -days)
 
 	query := `
 		SELECT
-			DATE(ws.completed_at) AS workout_date,
-			MAX(s.weight) AS max_weight,
+			DATE(ws.completed_at AT TIME ZONE $4) AS workout_date,
+			(ARRAY_AGG(s.weight ORDER BY s.weight_kg DESC, s.id) FILTER (WHERE s.weight IS NOT NULL))[1] AS max_weight,
+			COALESCE((ARRAY_AGG(s.weight_unit ORDER BY s.weight_kg DESC, s.id) FILTER (WHERE s.weight IS NOT NULL))[1], '') AS max_weight_unit,
 			MAX(s.reps) AS max_reps,
-			SUM(COALESCE(s.weight, 0) * s.reps) AS volume
+			SUM(COALESCE(s.weight_kg, 0) * s.reps) AS volume,
+			MAX(` + e1RMExpression(formula, "s.weight_kg", "s.reps") + `) FILTER (WHERE s.weight_kg > 0) AS e1rm,
+			ARRAY_AGG(DISTINCT e.notes) FILTER (WHERE e.notes IS NOT NULL) AS exercise_notes,
+			ARRAY_AGG(DISTINCT s.notes) FILTER (WHERE s.notes IS NOT NULL) AS set_notes
 		FROM exercises e
 		JOIN workout_sessions ws ON e.workout_session_id = ws.id
 		JOIN sets s ON s.exercise_id = e.id
 		WHERE ws.user_id = $1
 			AND ws.status = 'completed'
-			AND e.name = $2
+			AND e.exercise_definition_id = $2
 			AND ws.completed_at >= $3
-		GROUP BY DATE(ws.completed_at)
+			AND s.is_completed
+			AND s.set_type <> 'warmup'
+		GROUP BY DATE(ws.completed_at AT TIME ZONE $4)
 		ORDER BY workout_date ASC
 	`
 
-	rows, err := q.db.QueryContext(ctx, query, userID, exerciseName, cutoffDate)
+	rows, err := q.db.QueryContext(ctx, query, userID, definitionID, cutoffDate, loc.String())
 	if err != nil {
 		return nil, err
 	}
@@ -230,39 +486,103 @@ func (q *StatsQueries) GetExerciseProgress(ctx context.Context, userID int, exer
 	dataPoints := []models.ProgressDataPoint{}
 	for rows.Next() {
 		var point models.ProgressDataPoint
+		var exerciseNotes, setNotes []string
 		err := rows.Scan(
 			&point.Date,
 			&point.MaxWeight,
+			&point.MaxWeightUnit,
 			&point.MaxReps,
 			&point.Volume,
+			&point.E1RM,
+			pq.Array(&exerciseNotes),
+			pq.Array(&setNotes),
 		)
 		if err != nil {
 			return nil, err
 		}
+		point.Notes = mergeNotes(exerciseNotes, setNotes)
 		dataPoints = append(dataPoints, point)
 	}
 
 	return dataPoints, nil
 }
 
-func getWeekBounds(year, week int) (time.Time, time.Time) {
-	jan1 := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+// GetAverageRest averages the recorded rest before each working set,
+// per exercise, over completed workouts in the last days.
+func (q *StatsQueries) GetAverageRest(ctx context.Context, userID int, days int) ([]models.ExerciseRest, error) {
+	cutoffDate := time.Now().AddDate(0, 0, -days)
 
-	daysToMonday := int(time.Monday - jan1.Weekday())
-	if daysToMonday > 0 {
-		daysToMonday -= 7
+	query := `
+		SELECT
+			ed.id,
+			ed.name,
+			AVG(s.rest_seconds)::float8 AS average_rest,
+			COUNT(*) AS set_count
+		FROM exercises e
+		JOIN workout_sessions ws ON e.workout_session_id = ws.id
+		JOIN exercise_definitions ed ON e.exercise_definition_id = ed.id
+		JOIN sets s ON s.exercise_id = e.id
+		WHERE ws.user_id = $1
+			AND ws.status = 'completed'
+			AND ws.completed_at >= $2
+			AND s.rest_seconds IS NOT NULL
+			AND s.is_completed
+			AND s.set_type <> 'warmup'
+		GROUP BY ed.id, ed.name
+		ORDER BY ed.name ASC
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, userID, cutoffDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exercises := []models.ExerciseRest{}
+	for rows.Next() {
+		var rest models.ExerciseRest
+		err := rows.Scan(
+			&rest.ExerciseDefinitionID,
+			&rest.ExerciseName,
+			&rest.AverageRestSeconds,
+			&rest.SetCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, rest)
 	}
 
-	firstMonday := jan1.AddDate(0, 0, daysToMonday)
+	return exercises, nil
+}
+
+// getWeekBounds returns midnight on the Monday starting ISO week year-Wweek
+// in loc and midnight a week later. Week 1 is the week containing January 4.
+func getWeekBounds(year, week int, loc *time.Location) (time.Time, time.Time) {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	daysSinceMonday := (int(jan4.Weekday()) + 6) % 7
 
-	startOfWeek := firstMonday.AddDate(0, 0, (week-1)*7)
+	startOfWeek := jan4.AddDate(0, 0, (week-1)*7-daysSinceMonday)
 	endOfWeek := startOfWeek.AddDate(0, 0, 7)
 
 	return startOfWeek, endOfWeek
 }
 
 func formatWeek(year, week int) string {
-	return time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC).
-		AddDate(0, 0, (week-1)*7).
-		Format("2006-W02")
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// mergeNotes joins note lists in order, dropping repeats.
+func mergeNotes(lists ...[]string) []string {
+	var merged []string
+	seen := map[string]bool{}
+	for _, notes := range lists {
+		for _, note := range notes {
+			if !seen[note] {
+				seen[note] = true
+				merged = append(merged, note)
+			}
+		}
+	}
+	return merged
 }
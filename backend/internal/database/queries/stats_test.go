@@ -0,0 +1,74 @@
+package queries
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGetWeekBounds(t *testing.T) {
+	losAngeles, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		year      int
+		week      int
+		loc       *time.Location
+		wantStart string
+	}{
+		{"week 1 starts in the previous year", 2025, 1, time.UTC, "2024-12-30"},
+		{"week 1 starts on January 1", 2024, 1, time.UTC, "2024-01-01"},
+		{"week 1 starts after January 1", 2021, 1, time.UTC, "2021-01-04"},
+		{"mid year", 2024, 23, time.UTC, "2024-06-03"},
+		{"week 53", 2020, 53, time.UTC, "2020-12-28"},
+		{"across a DST change", 2024, 11, losAngeles, "2024-03-11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := getWeekBounds(tt.year, tt.week, tt.loc)
+
+			if got := start.Format("2006-01-02"); got != tt.wantStart {
+				t.Fatalf("start = %s, want %s", got, tt.wantStart)
+			}
+			if start.Weekday() != time.Monday || start.Hour() != 0 || start.Location() != tt.loc {
+				t.Fatalf("start = %v, want midnight Monday in %v", start, tt.loc)
+			}
+			if year, week := start.ISOWeek(); year != tt.year || week != tt.week {
+				t.Fatalf("start is in %d-W%02d, want %d-W%02d", year, week, tt.year, tt.week)
+			}
+			if want := start.AddDate(0, 0, 7); !end.Equal(want) || end.Hour() != 0 {
+				t.Fatalf("end = %v, want midnight %v", end, want)
+			}
+		})
+	}
+}
+
+func TestFormatWeek(t *testing.T) {
+	if got := formatWeek(2024, 3); got != "2024-W03" {
+		t.Fatalf("formatWeek = %q", got)
+	}
+}
+
+func TestMergeNotes(t *testing.T) {
+	tests := []struct {
+		name  string
+		lists [][]string
+		want  []string
+	}{
+		{"nothing", [][]string{nil, nil}, nil},
+		{"keeps order", [][]string{{"b", "a"}, {"c"}}, []string{"b", "a", "c"}},
+		{"drops repeats across lists", [][]string{{"grip slipped"}, {"grip slipped", "felt easy"}}, []string{"grip slipped", "felt easy"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeNotes(tt.lists...); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("mergeNotes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package queries
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/lib/pq"
+)
+
+// ErrRefreshTokenUsed is returned by RotateRefreshToken when the old token
+// was revoked before it could be rotated.
+var ErrRefreshTokenUsed = errors.New("refresh token already used")
+
+type TokenQueries struct {
+	db Querier
+}
+
+func NewTokenQueries(db Querier) *TokenQueries {
+	return &TokenQueries{db: db}
+}
+
+func (q *TokenQueries) CreateRefreshToken(ctx context.Context, userID int, familyID, tokenHash, accessJTI string, expiresAt time.Time) (*models.RefreshToken, error) {
+	query := `
+		INSERT INTO refresh_tokens (user_id, family_id, token_hash, access_jti, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, user_id, family_id, token_hash, access_jti, expires_at, revoked_at, replaced_by_id, created_at
+	`
+
+	var token models.RefreshToken
+	err := q.db.QueryRowContext(ctx, query, userID, familyID, tokenHash, accessJTI, expiresAt).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.AccessJTI,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedByID,
+		&token.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (q *TokenQueries) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, access_jti, expires_at, revoked_at, replaced_by_id, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	var token models.RefreshToken
+	err := q.db.QueryRowContext(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.AccessJTI,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedByID,
+		&token.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("refresh token not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken revokes the old token and issues its successor in the
+// same family in a single statement. The successor's ID is drawn up front so
+// the revocation can point at it, and it is only inserted if the revocation
+// matched: when the old token was already revoked (e.g. a concurrent rotation
+// won the race) nothing is written and ErrRefreshTokenUsed is returned.
+func (q *TokenQueries) RotateRefreshToken(ctx context.Context, oldTokenID int, tokenHash, accessJTI string, expiresAt time.Time) (*models.RefreshToken, error) {
+	query := `
+		WITH next_id AS (
+			SELECT nextval(pg_get_serial_sequence('refresh_tokens', 'id'))::INTEGER AS id
+		),
+		old_token AS (
+			UPDATE refresh_tokens
+			SET revoked_at = NOW(), replaced_by_id = next_id.id
+			FROM next_id
+			WHERE refresh_tokens.id = $1 AND refresh_tokens.revoked_at IS NULL
+			RETURNING refresh_tokens.user_id, refresh_tokens.family_id, refresh_tokens.replaced_by_id
+		)
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, access_jti, expires_at)
+		SELECT replaced_by_id, user_id, family_id, $2, $3, $4
+		FROM old_token
+		RETURNING id, user_id, family_id, token_hash, access_jti, expires_at, revoked_at, replaced_by_id, created_at
+	`
+
+	var token models.RefreshToken
+	err := q.db.QueryRowContext(ctx, query, oldTokenID, tokenHash, accessJTI, expiresAt).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.AccessJTI,
+		&token.ExpiresAt,
+		&token.RevokedAt,
+		&token.ReplacedByID,
+		&token.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, ErrRefreshTokenUsed
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// RevokeTokenFamily revokes every token in the family and returns the access
+// token IDs issued since issuedAfter, which may still be live.
+func (q *TokenQueries) RevokeTokenFamily(ctx context.Context, familyID string, issuedAfter time.Time) ([]string, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE family_id = $1 AND (revoked_at IS NULL OR created_at > $2)
+		RETURNING access_jti, created_at
+	`
+
+	return q.revoke(ctx, query, familyID, issuedAfter)
+}
+
+func (q *TokenQueries) RevokeUserTokens(ctx context.Context, userID int, issuedAfter time.Time) ([]string, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE user_id = $1 AND (revoked_at IS NULL OR created_at > $2)
+		RETURNING access_jti, created_at
+	`
+
+	return q.revoke(ctx, query, userID, issuedAfter)
+}
+
+func (q *TokenQueries) revoke(ctx context.Context, query string, arg interface{}, issuedAfter time.Time) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, query, arg, issuedAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jtis := []string{}
+	for rows.Next() {
+		var jti *string
+		var createdAt time.Time
+		if err := rows.Scan(&jti, &createdAt); err != nil {
+			return nil, err
+		}
+		if jti != nil && createdAt.After(issuedAfter) {
+			jtis = append(jtis, *jti)
+		}
+	}
+
+	return jtis, rows.Err()
+}
+
+// DenyAccessTokens records access token IDs as revoked until expiresAt and
+// prunes entries whose tokens have expired since.
+func (q *TokenQueries) DenyAccessTokens(ctx context.Context, jtis []string, expiresAt time.Time) error {
+	if _, err := q.db.ExecContext(ctx, `DELETE FROM revoked_access_tokens WHERE expires_at <= NOW()`); err != nil {
+		return err
+	}
+
+	if len(jtis) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO revoked_access_tokens (jti, expires_at)
+		SELECT jti, $2
+		FROM unnest($1::text[]) AS jti
+		ON CONFLICT (jti) DO UPDATE SET expires_at = GREATEST(revoked_access_tokens.expires_at, EXCLUDED.expires_at)
+	`
+
+	_, err := q.db.ExecContext(ctx, query, pq.Array(jtis), expiresAt)
+	return err
+}
+
+func (q *TokenQueries) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM revoked_access_tokens
+			WHERE jti = $1 AND expires_at > NOW()
+		)
+	`
+
+	var revoked bool
+	err := q.db.QueryRowContext(ctx, query, jti).Scan(&revoked)
+	return revoked, err
+}
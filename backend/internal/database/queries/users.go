@@ -9,10 +9,10 @@ import (
 )
 
 type UserQueries struct {
-	db *sql.DB
+	db Querier
 }
 
-func NewUserQueries(db *sql.DB) *UserQueries {
+func NewUserQueries(db Querier) *UserQueries {
 	return &UserQueries{db: db}
 }
 
@@ -20,7 +20,7 @@ func (q *UserQueries) CreateUser(ctx context.Context, email, passwordHash, usern
 	query := `
 		INSERT INTO users (email, password_hash, username)
 		VALUES ($1, $2, $3)
-		RETURNING id, email, password_hash, username, created_at, updated_at
+		RETURNING id, email, password_hash, username, preferred_unit, timezone, created_at, updated_at
 	`
 
 	var user models.User
@@ -29,6 +29,8 @@ func (q *UserQueries) CreateUser(ctx context.Context, email, passwordHash, usern
 		&user.Email,
 		&user.PasswordHash,
 		&user.Username,
+		&user.PreferredUnit,
+		&user.Timezone,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -42,7 +44,7 @@ func (q *UserQueries) CreateUser(ctx context.Context, email, passwordHash, usern
 
 func (q *UserQueries) GetUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, username, created_at, updated_at
+		SELECT id, email, password_hash, username, preferred_unit, timezone, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -53,6 +55,8 @@ func (q *UserQueries) GetUserByEmail(ctx context.Context, email string) (*models
 		&user.Email,
 		&user.PasswordHash,
 		&user.Username,
+		&user.PreferredUnit,
+		&user.Timezone,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -70,7 +74,7 @@ func (q *UserQueries) GetUserByEmail(ctx context.Context, email string) (*models
 
 func (q *UserQueries) GetUserByID(ctx context.Context, id int) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, username, created_at, updated_at
+		SELECT id, email, password_hash, username, preferred_unit, timezone, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -81,6 +85,39 @@ func (q *UserQueries) GetUserByID(ctx context.Context, id int) (*models.User, er
 		&user.Email,
 		&user.PasswordHash,
 		&user.Username,
+		&user.PreferredUnit,
+		&user.Timezone,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (q *UserQueries) UpdatePreferences(ctx context.Context, userID int, preferredUnit, timezone string) (*models.User, error) {
+	query := `
+		UPDATE users
+		SET preferred_unit = $1, timezone = $2, updated_at = NOW()
+		WHERE id = $3
+		RETURNING id, email, password_hash, username, preferred_unit, timezone, created_at, updated_at
+	`
+
+	var user models.User
+	err := q.db.QueryRowContext(ctx, query, preferredUnit, timezone, userID).Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Username,
+		&user.PreferredUnit,
+		&user.Timezone,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
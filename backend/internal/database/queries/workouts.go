@@ -4,15 +4,23 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/lib/pq"
 )
 
+const workoutColumns = `id, user_id, name, status, started_at, completed_at, abandoned_at, notes, version, created_at, updated_at`
+
+const exerciseColumns = `id, workout_session_id, exercise_definition_id, name, order_index, notes, version, created_at, updated_at`
+
+const setColumns = `id, exercise_id, set_number, reps, weight, weight_unit, is_bodyweight, is_completed, set_type, rpe, rir, tempo, rest_seconds, notes, created_at, updated_at`
+
 type WorkoutQueries struct {
-	db *sql.DB
+	db Querier
 }
 
-func NewWorkoutQueries(db *sql.DB) *WorkoutQueries {
+func NewWorkoutQueries(db Querier) *WorkoutQueries {
 	return &WorkoutQueries{db: db}
 }
 
@@ -20,48 +28,25 @@ func (q *WorkoutQueries) CreateWorkout(ctx context.Context, userID int, name str
 	query := `
 		INSERT INTO workout_sessions (user_id, name, status)
 		VALUES ($1, $2, 'active')
-		RETURNING id, user_id, name, status, started_at, completed_at, created_at, updated_at
-	`
-
-	var workout models.WorkoutSession
-	err := q.db.QueryRowContext(ctx, query, userID, name).Scan(
-		&workout.ID,
-		&workout.UserID,
-		&workout.Name,
-		&workout.Status,
-		&workout.StartedAt,
-		&workout.CompletedAt,
-		&workout.CreatedAt,
-		&workout.UpdatedAt,
-	)
+		RETURNING ` + workoutColumns
 
+	workout, err := scanWorkout(q.db.QueryRowContext(ctx, query, userID, name))
 	if err != nil {
 		return nil, err
 	}
 
 	workout.Exercises = []models.Exercise{}
-	return &workout, nil
+	return workout, nil
 }
 
 func (q *WorkoutQueries) GetWorkoutByID(ctx context.Context, workoutID int) (*models.WorkoutSession, error) {
 	query := `
-		SELECT id, user_id, name, status, started_at, completed_at, created_at, updated_at
+		SELECT ` + workoutColumns + `
 		FROM workout_sessions
 		WHERE id = $1
 	`
 
-	var workout models.WorkoutSession
-	err := q.db.QueryRowContext(ctx, query, workoutID).Scan(
-		&workout.ID,
-		&workout.UserID,
-		&workout.Name,
-		&workout.Status,
-		&workout.StartedAt,
-		&workout.CompletedAt,
-		&workout.CreatedAt,
-		&workout.UpdatedAt,
-	)
-
+	workout, err := scanWorkout(q.db.QueryRowContext(ctx, query, workoutID))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("workout not found")
 	}
@@ -70,36 +55,99 @@ func (q *WorkoutQueries) GetWorkoutByID(ctx context.Context, workoutID int) (*mo
 		return nil, err
 	}
 
-	exercises, err := q.GetExercisesByWorkoutID(ctx, workoutID)
+	if err := q.loadWorkoutDetails(ctx, []*models.WorkoutSession{workout}); err != nil {
+		return nil, err
+	}
+
+	return workout, nil
+}
+
+// GetWorkoutsByIDs loads many workouts with their exercises, sets and pauses
+// in four queries, however many workouts there are. IDs that don't exist are
+// skipped; the rest come back in the order given.
+func (q *WorkoutQueries) GetWorkoutsByIDs(ctx context.Context, workoutIDs []int) ([]models.WorkoutSession, error) {
+	if len(workoutIDs) == 0 {
+		return []models.WorkoutSession{}, nil
+	}
+
+	query := `
+		SELECT ` + workoutColumns + `
+		FROM workout_sessions
+		WHERE id = ANY($1)
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, pq.Array(workoutIDs))
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	byID := map[int]*models.WorkoutSession{}
+	for rows.Next() {
+		workout, err := scanWorkout(rows)
+		if err != nil {
+			return nil, err
+		}
+		byID[workout.ID] = workout
+	}
 
-	workout.Exercises = exercises
-	return &workout, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	loaded := []*models.WorkoutSession{}
+	for _, id := range workoutIDs {
+		if workout, ok := byID[id]; ok {
+			loaded = append(loaded, workout)
+			delete(byID, id)
+		}
+	}
+
+	if err := q.loadWorkoutDetails(ctx, loaded); err != nil {
+		return nil, err
+	}
+
+	workouts := make([]models.WorkoutSession, len(loaded))
+	for i, workout := range loaded {
+		workouts[i] = *workout
+	}
+
+	return workouts, nil
+}
+
+// LockWorkout loads the session row without its exercises and holds a row
+// lock on it until the surrounding transaction ends, so concurrent edits to
+// the same workout are serialized.
+func (q *WorkoutQueries) LockWorkout(ctx context.Context, workoutID int) (*models.WorkoutSession, error) {
+	query := `
+		SELECT ` + workoutColumns + `
+		FROM workout_sessions
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	workout, err := scanWorkout(q.db.QueryRowContext(ctx, query, workoutID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("workout not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return workout, nil
 }
 
 func (q *WorkoutQueries) GetActiveWorkout(ctx context.Context, userID int) (*models.WorkoutSession, error) {
 	query := `
-		SELECT id, user_id, name, status, started_at, completed_at, created_at, updated_at
+		SELECT ` + workoutColumns + `
 		FROM workout_sessions
-		WHERE user_id = $1 AND status = 'active'
+		WHERE user_id = $1 AND status IN ('active', 'paused')
 		ORDER BY started_at DESC
 		LIMIT 1
 	`
 
-	var workout models.WorkoutSession
-	err := q.db.QueryRowContext(ctx, query, userID).Scan(
-		&workout.ID,
-		&workout.UserID,
-		&workout.Name,
-		&workout.Status,
-		&workout.StartedAt,
-		&workout.CompletedAt,
-		&workout.CreatedAt,
-		&workout.UpdatedAt,
-	)
-
+	workout, err := scanWorkout(q.db.QueryRowContext(ctx, query, userID))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -108,20 +156,35 @@ func (q *WorkoutQueries) GetActiveWorkout(ctx context.Context, userID int) (*mod
 		return nil, err
 	}
 
-	exercises, err := q.GetExercisesByWorkoutID(ctx, workout.ID)
-	if err != nil {
+	if err := q.loadWorkoutDetails(ctx, []*models.WorkoutSession{workout}); err != nil {
 		return nil, err
 	}
 
-	workout.Exercises = exercises
-	return &workout, nil
+	return workout, nil
 }
 
+// CompleteWorkout also discards routine placeholder sets that were never
+// performed so they don't show up in history or stats. A paused workout is
+// resumed at the moment it completes. The discarded sets leave gaps in
+// set_number until RenumberWorkoutSets runs in the same transaction.
 func (q *WorkoutQueries) CompleteWorkout(ctx context.Context, workoutID int) error {
 	query := `
+		WITH discarded AS (
+			DELETE FROM sets s
+			USING exercises e, workout_sessions ws
+			WHERE s.exercise_id = e.id
+				AND e.workout_session_id = ws.id
+				AND ws.id = $1
+				AND ws.status IN ('active', 'paused')
+				AND NOT s.is_completed
+		), resumed AS (
+			UPDATE workout_pauses
+			SET resumed_at = NOW()
+			WHERE workout_session_id = $1 AND resumed_at IS NULL
+		)
 		UPDATE workout_sessions
-		SET status = 'completed', completed_at = NOW(), updated_at = NOW()
-		WHERE id = $1 AND status = 'active'
+		SET status = 'completed', completed_at = NOW(), version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND status IN ('active', 'paused')
 	`
 
 	result, err := q.db.ExecContext(ctx, query, workoutID)
@@ -141,6 +204,233 @@ func (q *WorkoutQueries) CompleteWorkout(ctx context.Context, workoutID int) err
 	return nil
 }
 
+// UpdateWorkout overwrites the session's name, times and notes. completedAt
+// is ignored for workouts that are not completed.
+func (q *WorkoutQueries) UpdateWorkout(ctx context.Context, workoutID int, name string, startedAt time.Time, completedAt *time.Time, notes *string) error {
+	query := `
+		UPDATE workout_sessions
+		SET name = $2,
+			started_at = $3,
+			completed_at = CASE WHEN status = 'completed' THEN $4 ELSE completed_at END,
+			notes = $5,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := q.db.ExecContext(ctx, query, workoutID, name, startedAt, completedAt, notes)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("workout not found")
+	}
+
+	return nil
+}
+
+// PauseWorkout marks an active workout paused and opens a pause.
+func (q *WorkoutQueries) PauseWorkout(ctx context.Context, workoutID int) error {
+	query := `
+		WITH paused AS (
+			UPDATE workout_sessions
+			SET status = 'paused', version = version + 1, updated_at = NOW()
+			WHERE id = $1 AND status = 'active'
+			RETURNING id
+		)
+		INSERT INTO workout_pauses (workout_session_id)
+		SELECT id FROM paused
+	`
+
+	result, err := q.db.ExecContext(ctx, query, workoutID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("workout not found or not active")
+	}
+
+	return nil
+}
+
+// ResumeWorkout closes the open pause of a paused workout and makes it
+// active again.
+func (q *WorkoutQueries) ResumeWorkout(ctx context.Context, workoutID int) error {
+	query := `
+		WITH resumed AS (
+			UPDATE workout_sessions
+			SET status = 'active', version = version + 1, updated_at = NOW()
+			WHERE id = $1 AND status = 'paused'
+			RETURNING id
+		)
+		UPDATE workout_pauses
+		SET resumed_at = NOW()
+		WHERE workout_session_id = (SELECT id FROM resumed) AND resumed_at IS NULL
+	`
+
+	result, err := q.db.ExecContext(ctx, query, workoutID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("workout not found or not paused")
+	}
+
+	return nil
+}
+
+// AbandonWorkout discards an unfinished workout. Its exercises and sets are
+// kept but never count toward stats.
+func (q *WorkoutQueries) AbandonWorkout(ctx context.Context, workoutID int) error {
+	query := `
+		WITH resumed AS (
+			UPDATE workout_pauses
+			SET resumed_at = NOW()
+			WHERE workout_session_id = $1 AND resumed_at IS NULL
+		)
+		UPDATE workout_sessions
+		SET status = 'abandoned', abandoned_at = NOW(), version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND status IN ('active', 'paused')
+	`
+
+	result, err := q.db.ExecContext(ctx, query, workoutID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("workout not found or already finished")
+	}
+
+	return nil
+}
+
+// AbandonStaleWorkouts abandons every unfinished workout started before
+// startedBefore and returns the IDs of the users they belonged to.
+func (q *WorkoutQueries) AbandonStaleWorkouts(ctx context.Context, startedBefore time.Time) ([]int, error) {
+	query := `
+		WITH stale AS (
+			UPDATE workout_sessions
+			SET status = 'abandoned', abandoned_at = NOW(), version = version + 1, updated_at = NOW()
+			WHERE status IN ('active', 'paused') AND started_at < $1
+			RETURNING id, user_id
+		), resumed AS (
+			UPDATE workout_pauses
+			SET resumed_at = NOW()
+			WHERE workout_session_id IN (SELECT id FROM stale) AND resumed_at IS NULL
+		)
+		SELECT user_id FROM stale
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, startedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	userIDs := []int{}
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs, rows.Err()
+}
+
+// loadWorkoutDetails attaches exercises with their sets and pauses to
+// workouts, then computes their active time. It runs one query for each of
+// exercises, sets and pauses regardless of how many workouts are given.
+func (q *WorkoutQueries) loadWorkoutDetails(ctx context.Context, workouts []*models.WorkoutSession) error {
+	if len(workouts) == 0 {
+		return nil
+	}
+
+	workoutIDs := make([]int, len(workouts))
+	for i, workout := range workouts {
+		workoutIDs[i] = workout.ID
+	}
+
+	exercises, err := q.getExercisesByWorkoutIDs(ctx, workoutIDs)
+	if err != nil {
+		return err
+	}
+
+	pauses, err := q.getPausesByWorkoutIDs(ctx, workoutIDs)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, workout := range workouts {
+		workout.Exercises = exercises[workout.ID]
+		if workout.Exercises == nil {
+			workout.Exercises = []models.Exercise{}
+		}
+
+		workout.Pauses = pauses[workout.ID]
+		if workout.Pauses == nil {
+			workout.Pauses = []models.WorkoutPause{}
+		}
+
+		workout.SetActiveSeconds(now)
+	}
+
+	return nil
+}
+
+// getPausesByWorkoutIDs returns each workout's pauses, oldest first.
+func (q *WorkoutQueries) getPausesByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]models.WorkoutPause, error) {
+	query := `
+		SELECT workout_session_id, id, paused_at, resumed_at
+		FROM workout_pauses
+		WHERE workout_session_id = ANY($1)
+		ORDER BY workout_session_id ASC, paused_at ASC
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, pq.Array(workoutIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pauses := map[int][]models.WorkoutPause{}
+	for rows.Next() {
+		var workoutID int
+		var pause models.WorkoutPause
+		if err := rows.Scan(&workoutID, &pause.ID, &pause.PausedAt, &pause.ResumedAt); err != nil {
+			return nil, err
+		}
+		pauses[workoutID] = append(pauses[workoutID], pause)
+	}
+
+	return pauses, rows.Err()
+}
+
 func (q *WorkoutQueries) DeleteWorkout(ctx context.Context, workoutID int) error {
 	query := `DELETE FROM workout_sessions WHERE id = $1`
 
@@ -161,89 +451,81 @@ func (q *WorkoutQueries) DeleteWorkout(ctx context.Context, workoutID int) error
 	return nil
 }
 
-func (q *WorkoutQueries) GetExercisesByWorkoutID(ctx context.Context, workoutID int) ([]models.Exercise, error) {
+// getExercisesByWorkoutIDs returns each workout's exercises in order with
+// their sets, using one query for the exercises and one for all their sets.
+func (q *WorkoutQueries) getExercisesByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]models.Exercise, error) {
 	query := `
-		SELECT id, workout_session_id, name, order_index, created_at, updated_at
+		SELECT ` + exerciseColumns + `
 		FROM exercises
-		WHERE workout_session_id = $1
-		ORDER BY order_index ASC
+		WHERE workout_session_id = ANY($1)
+		ORDER BY workout_session_id ASC, order_index ASC
 	`
 
-	rows, err := q.db.QueryContext(ctx, query, workoutID)
+	rows, err := q.db.QueryContext(ctx, query, pq.Array(workoutIDs))
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	exercises := []models.Exercise{}
+	loaded := []models.Exercise{}
+	exerciseIDs := []int{}
 	for rows.Next() {
-		var exercise models.Exercise
-		err := rows.Scan(
-			&exercise.ID,
-			&exercise.WorkoutSessionID,
-			&exercise.Name,
-			&exercise.OrderIndex,
-			&exercise.CreatedAt,
-			&exercise.UpdatedAt,
-		)
+		exercise, err := scanExercise(rows)
 		if err != nil {
 			return nil, err
 		}
+		loaded = append(loaded, *exercise)
+		exerciseIDs = append(exerciseIDs, exercise.ID)
+	}
 
-		sets, err := q.GetSetsByExerciseID(ctx, exercise.ID)
-		if err != nil {
-			return nil, err
-		}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Drain the exercises before querying sets; a connection cannot read two
+	// result sets at once inside a transaction.
+	rows.Close()
+
+	sets, err := q.getSetsByExerciseIDs(ctx, exerciseIDs)
+	if err != nil {
+		return nil, err
+	}
 
-		exercise.Sets = sets
-		exercises = append(exercises, exercise)
+	exercises := map[int][]models.Exercise{}
+	for _, exercise := range loaded {
+		exercise.Sets = sets[exercise.ID]
+		if exercise.Sets == nil {
+			exercise.Sets = []models.Set{}
+		}
+		exercises[exercise.WorkoutSessionID] = append(exercises[exercise.WorkoutSessionID], exercise)
 	}
 
 	return exercises, nil
 }
 
-func (q *WorkoutQueries) CreateExercise(ctx context.Context, workoutID int, name string, orderIndex int) (*models.Exercise, error) {
+func (q *WorkoutQueries) CreateExercise(ctx context.Context, workoutID, definitionID int, name string, orderIndex int, notes *string) (*models.Exercise, error) {
 	query := `
-		INSERT INTO exercises (workout_session_id, name, order_index)
-		VALUES ($1, $2, $3)
-		RETURNING id, workout_session_id, name, order_index, created_at, updated_at
-	`
-
-	var exercise models.Exercise
-	err := q.db.QueryRowContext(ctx, query, workoutID, name, orderIndex).Scan(
-		&exercise.ID,
-		&exercise.WorkoutSessionID,
-		&exercise.Name,
-		&exercise.OrderIndex,
-		&exercise.CreatedAt,
-		&exercise.UpdatedAt,
-	)
+		INSERT INTO exercises (workout_session_id, exercise_definition_id, name, order_index, notes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + exerciseColumns
 
+	exercise, err := scanExercise(q.db.QueryRowContext(ctx, query, workoutID, definitionID, name, orderIndex, notes))
 	if err != nil {
 		return nil, err
 	}
 
 	exercise.Sets = []models.Set{}
-	return &exercise, nil
+	return exercise, nil
 }
 
 func (q *WorkoutQueries) GetExerciseByID(ctx context.Context, exerciseID int) (*models.Exercise, error) {
 	query := `
-		SELECT id, workout_session_id, name, order_index, created_at, updated_at
+		SELECT ` + exerciseColumns + `
 		FROM exercises
 		WHERE id = $1
 	`
 
-	var exercise models.Exercise
-	err := q.db.QueryRowContext(ctx, query, exerciseID).Scan(
-		&exercise.ID,
-		&exercise.WorkoutSessionID,
-		&exercise.Name,
-		&exercise.OrderIndex,
-		&exercise.CreatedAt,
-		&exercise.UpdatedAt,
-	)
-
+	exercise, err := scanExercise(q.db.QueryRowContext(ctx, query, exerciseID))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("exercise not found")
 	}
@@ -258,17 +540,41 @@ func (q *WorkoutQueries) GetExerciseByID(ctx context.Context, exerciseID int) (*
 	}
 
 	exercise.Sets = sets
-	return &exercise, nil
+	return exercise, nil
 }
 
-func (q *WorkoutQueries) UpdateExerciseName(ctx context.Context, exerciseID int, name string) error {
+func (q *WorkoutQueries) UpdateExerciseName(ctx context.Context, exerciseID, definitionID int, name string) error {
 	query := `
 		UPDATE exercises
-		SET name = $1, updated_at = NOW()
+		SET exercise_definition_id = $1, name = $2, updated_at = NOW()
+		WHERE id = $3
+	`
+
+	result, err := q.db.ExecContext(ctx, query, definitionID, name, exerciseID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("exercise not found")
+	}
+
+	return nil
+}
+
+func (q *WorkoutQueries) UpdateExerciseNotes(ctx context.Context, exerciseID int, notes *string) error {
+	query := `
+		UPDATE exercises
+		SET notes = $1, updated_at = NOW()
 		WHERE id = $2
 	`
 
-	result, err := q.db.ExecContext(ctx, query, name, exerciseID)
+	result, err := q.db.ExecContext(ctx, query, notes, exerciseID)
 	if err != nil {
 		return err
 	}
@@ -285,6 +591,24 @@ func (q *WorkoutQueries) UpdateExerciseName(ctx context.Context, exerciseID int,
 	return nil
 }
 
+// BumpVersions records a change to the workout and, unless exerciseID is
+// zero, to that exercise. An exercise that no longer exists is ignored.
+func (q *WorkoutQueries) BumpVersions(ctx context.Context, workoutID, exerciseID int) error {
+	query := `
+		WITH exercise AS (
+			UPDATE exercises
+			SET version = version + 1
+			WHERE id = $2
+		)
+		UPDATE workout_sessions
+		SET version = version + 1
+		WHERE id = $1
+	`
+
+	_, err := q.db.ExecContext(ctx, query, workoutID, exerciseID)
+	return err
+}
+
 func (q *WorkoutQueries) DeleteExercise(ctx context.Context, exerciseID int) error {
 	query := `DELETE FROM exercises WHERE id = $1`
 
@@ -323,7 +647,7 @@ func (q *WorkoutQueries) GetNextExerciseOrderIndex(ctx context.Context, workoutI
 
 func (q *WorkoutQueries) GetSetsByExerciseID(ctx context.Context, exerciseID int) ([]models.Set, error) {
 	query := `
-		SELECT id, exercise_id, set_number, reps, weight, is_bodyweight, created_at, updated_at
+		SELECT ` + setColumns + `
 		FROM sets
 		WHERE exercise_id = $1
 		ORDER BY set_number ASC
@@ -337,46 +661,267 @@ func (q *WorkoutQueries) GetSetsByExerciseID(ctx context.Context, exerciseID int
 
 	sets := []models.Set{}
 	for rows.Next() {
-		var set models.Set
-		err := rows.Scan(
-			&set.ID,
-			&set.ExerciseID,
-			&set.SetNumber,
-			&set.Reps,
-			&set.Weight,
-			&set.IsBodyweight,
-			&set.CreatedAt,
-			&set.UpdatedAt,
-		)
+		set, err := scanSet(rows)
 		if err != nil {
 			return nil, err
 		}
 
-		sets = append(sets, set)
+		sets = append(sets, *set)
 	}
 
 	return sets, nil
 }
 
-func (q *WorkoutQueries) CreateSet(ctx context.Context, exerciseID, setNumber, reps int, weight *float64, isBodyweight bool) (*models.Set, error) {
+// getSetsByExerciseIDs returns each exercise's sets by set number.
+func (q *WorkoutQueries) getSetsByExerciseIDs(ctx context.Context, exerciseIDs []int) (map[int][]models.Set, error) {
+	sets := map[int][]models.Set{}
+	if len(exerciseIDs) == 0 {
+		return sets, nil
+	}
+
 	query := `
-		INSERT INTO sets (exercise_id, set_number, reps, weight, is_bodyweight)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, exercise_id, set_number, reps, weight, is_bodyweight, created_at, updated_at
+		SELECT ` + setColumns + `
+		FROM sets
+		WHERE exercise_id = ANY($1)
+		ORDER BY exercise_id ASC, set_number ASC
 	`
 
+	rows, err := q.db.QueryContext(ctx, query, pq.Array(exerciseIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		set, err := scanSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		sets[set.ExerciseID] = append(sets[set.ExerciseID], *set)
+	}
+
+	return sets, rows.Err()
+}
+
+// CreateSet inserts set under set.ExerciseID at set.SetNumber.
+func (q *WorkoutQueries) CreateSet(ctx context.Context, set *models.Set) (*models.Set, error) {
+	query := `
+		INSERT INTO sets (exercise_id, set_number, reps, weight, weight_unit, is_bodyweight, is_completed, set_type, rpe, rir, tempo, rest_seconds, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING ` + setColumns
+
+	return scanSet(q.db.QueryRowContext(ctx, query,
+		set.ExerciseID,
+		set.SetNumber,
+		set.Reps,
+		set.Weight,
+		set.WeightUnit,
+		set.IsBodyweight,
+		set.IsCompleted,
+		set.SetType,
+		set.RPE,
+		set.RIR,
+		set.Tempo,
+		set.RestSeconds,
+		set.Notes,
+	))
+}
+
+func (q *WorkoutQueries) DeleteSetsByExerciseID(ctx context.Context, exerciseID int) error {
+	query := `DELETE FROM sets WHERE exercise_id = $1`
+
+	_, err := q.db.ExecContext(ctx, query, exerciseID)
+	return err
+}
+
+func (q *WorkoutQueries) GetSetByID(ctx context.Context, setID int) (*models.Set, error) {
+	query := `
+		SELECT ` + setColumns + `
+		FROM sets
+		WHERE id = $1
+	`
+
+	set, err := scanSet(q.db.QueryRowContext(ctx, query, setID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("set not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return set, nil
+}
+
+func (q *WorkoutQueries) UpdateSet(ctx context.Context, set *models.Set) (*models.Set, error) {
+	query := `
+		UPDATE sets
+		SET reps = $1, weight = $2, weight_unit = $3, is_bodyweight = $4, is_completed = $5, set_type = $6,
+			rpe = $7, rir = $8, tempo = $9, rest_seconds = $10, notes = $11, updated_at = NOW()
+		WHERE id = $12
+		RETURNING ` + setColumns
+
+	updated, err := scanSet(q.db.QueryRowContext(ctx, query,
+		set.Reps,
+		set.Weight,
+		set.WeightUnit,
+		set.IsBodyweight,
+		set.IsCompleted,
+		set.SetType,
+		set.RPE,
+		set.RIR,
+		set.Tempo,
+		set.RestSeconds,
+		set.Notes,
+		set.ID,
+	))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("set not found")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+func (q *WorkoutQueries) DeleteSet(ctx context.Context, setID int) error {
+	query := `DELETE FROM sets WHERE id = $1`
+
+	result, err := q.db.ExecContext(ctx, query, setID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return fmt.Errorf("set not found")
+	}
+
+	return nil
+}
+
+// ReorderSets assigns set_number 1..n following the order of setIDs. Only
+// rows whose number actually changes are touched, so IDs and timestamps of
+// the other sets stay as they were.
+func (q *WorkoutQueries) ReorderSets(ctx context.Context, exerciseID int, setIDs []int) error {
+	query := `
+		UPDATE sets s
+		SET set_number = o.position
+		FROM unnest($2::int[]) WITH ORDINALITY AS o(id, position)
+		WHERE s.id = o.id
+			AND s.exercise_id = $1
+			AND s.set_number <> o.position
+	`
+
+	_, err := q.db.ExecContext(ctx, query, exerciseID, pq.Array(setIDs))
+	return err
+}
+
+// RenumberSets closes any gaps in set_number left behind by a deletion.
+func (q *WorkoutQueries) RenumberSets(ctx context.Context, exerciseID int) error {
+	query := `
+		UPDATE sets s
+		SET set_number = r.position
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY set_number ASC, id ASC) AS position
+			FROM sets
+			WHERE exercise_id = $1
+		) r
+		WHERE s.id = r.id
+			AND s.set_number <> r.position
+	`
+
+	_, err := q.db.ExecContext(ctx, query, exerciseID)
+	return err
+}
+
+// RenumberWorkoutSets runs RenumberSets over every exercise in a workout.
+func (q *WorkoutQueries) RenumberWorkoutSets(ctx context.Context, workoutID int) error {
+	query := `
+		UPDATE sets s
+		SET set_number = r.position
+		FROM (
+			SELECT s.id, ROW_NUMBER() OVER (PARTITION BY s.exercise_id ORDER BY s.set_number ASC, s.id ASC) AS position
+			FROM sets s
+			JOIN exercises e ON s.exercise_id = e.id
+			WHERE e.workout_session_id = $1
+		) r
+		WHERE s.id = r.id
+			AND s.set_number <> r.position
+	`
+
+	_, err := q.db.ExecContext(ctx, query, workoutID)
+	return err
+}
+
+func scanWorkout(row rowScanner) (*models.WorkoutSession, error) {
+	var workout models.WorkoutSession
+	err := row.Scan(
+		&workout.ID,
+		&workout.UserID,
+		&workout.Name,
+		&workout.Status,
+		&workout.StartedAt,
+		&workout.CompletedAt,
+		&workout.AbandonedAt,
+		&workout.Notes,
+		&workout.Version,
+		&workout.CreatedAt,
+		&workout.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workout, nil
+}
+
+func scanExercise(row rowScanner) (*models.Exercise, error) {
+	var exercise models.Exercise
+	err := row.Scan(
+		&exercise.ID,
+		&exercise.WorkoutSessionID,
+		&exercise.ExerciseDefinitionID,
+		&exercise.Name,
+		&exercise.OrderIndex,
+		&exercise.Notes,
+		&exercise.Version,
+		&exercise.CreatedAt,
+		&exercise.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &exercise, nil
+}
+
+func scanSet(row rowScanner) (*models.Set, error) {
 	var set models.Set
-	err := q.db.QueryRowContext(ctx, query, exerciseID, setNumber, reps, weight, isBodyweight).Scan(
+	err := row.Scan(
 		&set.ID,
 		&set.ExerciseID,
 		&set.SetNumber,
 		&set.Reps,
 		&set.Weight,
+		&set.WeightUnit,
 		&set.IsBodyweight,
+		&set.IsCompleted,
+		&set.SetType,
+		&set.RPE,
+		&set.RIR,
+		&set.Tempo,
+		&set.RestSeconds,
+		&set.Notes,
 		&set.CreatedAt,
 		&set.UpdatedAt,
 	)
-
 	if err != nil {
 		return nil, err
 	}
@@ -384,9 +929,255 @@ func (q *WorkoutQueries) CreateSet(ctx context.Context, exerciseID, setNumber, r
 	return &set, nil
 }
 
-func (q *WorkoutQueries) DeleteSetsByExerciseID(ctx context.Context, exerciseID int) error {
-	query := `DELETE FROM sets WHERE exercise_id = $1`
+// FindWorkoutsByStart returns the user's workouts that started at any of
+// startedAts, without exercises. Imports use it to skip workouts they have
+// already brought in.
+func (q *WorkoutQueries) FindWorkoutsByStart(ctx context.Context, userID int, startedAts []time.Time) ([]models.WorkoutSession, error) {
+	query := `
+		SELECT ` + workoutColumns + `
+		FROM workout_sessions
+		WHERE user_id = $1 AND started_at = ANY($2::timestamptz[])
+	`
 
-	_, err := q.db.ExecContext(ctx, query, exerciseID)
+	rows, err := q.db.QueryContext(ctx, query, userID, pq.Array(formatTimestamps(startedAts)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workouts := []models.WorkoutSession{}
+	for rows.Next() {
+		workout, err := scanWorkout(rows)
+		if err != nil {
+			return nil, err
+		}
+		workouts = append(workouts, *workout)
+	}
+
+	return workouts, rows.Err()
+}
+
+// CreateCompletedWorkouts inserts already-finished workouts in one statement
+// and returns their IDs in input order. Start time and name must be unique
+// within the batch; they are how inserted rows are matched back to input.
+func (q *WorkoutQueries) CreateCompletedWorkouts(ctx context.Context, userID int, workouts []models.WorkoutSession) ([]int, error) {
+	names := make([]string, len(workouts))
+	startedAts := make([]time.Time, len(workouts))
+	completedAts := make([]time.Time, len(workouts))
+	notes := make([]*string, len(workouts))
+	for i, workout := range workouts {
+		names[i] = workout.Name
+		notes[i] = workout.Notes
+		startedAts[i] = workout.StartedAt
+		completedAts[i] = workout.StartedAt
+		if workout.CompletedAt != nil {
+			completedAts[i] = *workout.CompletedAt
+		}
+	}
+
+	query := `
+		WITH input AS (
+			SELECT *
+			FROM unnest($2::text[], $3::timestamptz[], $4::timestamptz[], $5::text[])
+				WITH ORDINALITY AS i(name, started_at, completed_at, notes, position)
+		), inserted AS (
+			INSERT INTO workout_sessions (user_id, name, status, started_at, completed_at, notes)
+			SELECT $1, name, 'completed', started_at, completed_at, notes
+			FROM input
+			ORDER BY position
+			RETURNING id, name, started_at
+		)
+		SELECT inserted.id
+		FROM inserted
+		JOIN input ON input.name = inserted.name AND input.started_at = inserted.started_at
+		ORDER BY input.position
+	`
+
+	rows, err := q.db.QueryContext(ctx, query, userID,
+		pq.Array(names),
+		pq.Array(formatTimestamps(startedAts)),
+		pq.Array(formatTimestamps(completedAts)),
+		pq.Array(notes),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanIDs(rows, len(workouts))
+}
+
+// CreateExercises inserts exercises for several workouts in one statement and
+// returns their IDs in input order. Each (workout, order index) pair must be
+// unique within the batch.
+func (q *WorkoutQueries) CreateExercises(ctx context.Context, exercises []models.Exercise) ([]int, error) {
+	workoutIDs := make([]int, len(exercises))
+	definitionIDs := make([]int, len(exercises))
+	names := make([]string, len(exercises))
+	orderIndexes := make([]int, len(exercises))
+	notes := make([]*string, len(exercises))
+	for i, exercise := range exercises {
+		workoutIDs[i] = exercise.WorkoutSessionID
+		definitionIDs[i] = exercise.ExerciseDefinitionID
+		names[i] = exercise.Name
+		orderIndexes[i] = exercise.OrderIndex
+		notes[i] = exercise.Notes
+	}
+
+	query := `
+		WITH input AS (
+			SELECT *
+			FROM unnest($1::int[], $2::int[], $3::text[], $4::int[], $5::text[])
+				WITH ORDINALITY AS i(workout_session_id, exercise_definition_id, name, order_index, notes, position)
+		), inserted AS (
+			INSERT INTO exercises (workout_session_id, exercise_definition_id, name, order_index, notes)
+			SELECT workout_session_id, exercise_definition_id, name, order_index, notes
+			FROM input
+			ORDER BY position
+			RETURNING id, workout_session_id, order_index
+		)
+		SELECT inserted.id
+		FROM inserted
+		JOIN input ON input.workout_session_id = inserted.workout_session_id AND input.order_index = inserted.order_index
+		ORDER BY input.position
+	`
+
+	rows, err := q.db.QueryContext(ctx, query,
+		pq.Array(workoutIDs),
+		pq.Array(definitionIDs),
+		pq.Array(names),
+		pq.Array(orderIndexes),
+		pq.Array(notes),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanIDs(rows, len(exercises))
+}
+
+// CreateSets inserts sets for several exercises in one statement.
+func (q *WorkoutQueries) CreateSets(ctx context.Context, sets []models.Set) error {
+	exerciseIDs := make([]int, len(sets))
+	setNumbers := make([]int, len(sets))
+	reps := make([]int, len(sets))
+	weights := make([]*float64, len(sets))
+	weightUnits := make([]string, len(sets))
+	isBodyweight := make([]bool, len(sets))
+	isCompleted := make([]bool, len(sets))
+	setTypes := make([]string, len(sets))
+	rpes := make([]*float64, len(sets))
+	rirs := make([]*int, len(sets))
+	tempos := make([]*string, len(sets))
+	restSeconds := make([]*int, len(sets))
+	notes := make([]*string, len(sets))
+	for i, set := range sets {
+		exerciseIDs[i] = set.ExerciseID
+		setNumbers[i] = set.SetNumber
+		reps[i] = set.Reps
+		weights[i] = set.Weight
+		weightUnits[i] = set.WeightUnit
+		isBodyweight[i] = set.IsBodyweight
+		isCompleted[i] = set.IsCompleted
+		setTypes[i] = set.SetType
+		rpes[i] = set.RPE
+		rirs[i] = set.RIR
+		tempos[i] = set.Tempo
+		restSeconds[i] = set.RestSeconds
+		notes[i] = set.Notes
+	}
+
+	query := `
+		INSERT INTO sets (exercise_id, set_number, reps, weight, weight_unit, is_bodyweight, is_completed, set_type, rpe, rir, tempo, rest_seconds, notes)
+		SELECT *
+		FROM unnest(
+			$1::int[], $2::int[], $3::int[], $4::numeric[], $5::text[], $6::boolean[],
+			$7::boolean[], $8::text[], $9::numeric[], $10::int[], $11::text[], $12::int[], $13::text[]
+		)
+	`
+
+	_, err := q.db.ExecContext(ctx, query,
+		pq.Array(exerciseIDs),
+		pq.Array(setNumbers),
+		pq.Array(reps),
+		pq.Array(weights),
+		pq.Array(weightUnits),
+		pq.Array(isBodyweight),
+		pq.Array(isCompleted),
+		pq.Array(setTypes),
+		pq.Array(rpes),
+		pq.Array(rirs),
+		pq.Array(tempos),
+		pq.Array(restSeconds),
+		pq.Array(notes),
+	)
 	return err
 }
+
+func scanIDs(rows *sql.Rows, expected int) ([]int, error) {
+	ids := make([]int, 0, expected)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(ids) != expected {
+		return nil, fmt.Errorf("batch insert returned %d rows, expected %d", len(ids), expected)
+	}
+
+	return ids, nil
+}
+
+// formatTimestamps renders times as RFC 3339 text for a timestamptz[]
+// parameter, keeping the zone offset and sub-second precision.
+func formatTimestamps(times []time.Time) []string {
+	formatted := make([]string, len(times))
+	for i, t := range times {
+		formatted[i] = t.Format(time.RFC3339Nano)
+	}
+	return formatted
+}
+
+// LockWorkoutHistory holds a per-user advisory lock until the surrounding
+// transaction ends. Writers that add past workouts take it so two requests
+// cannot both pass the duplicate or overlap check and insert clashing rows.
+func (q *WorkoutQueries) LockWorkoutHistory(ctx context.Context, userID int) error {
+	_, err := q.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('workout_history'), $1)`, userID)
+	return err
+}
+
+// FindOverlappingWorkout returns a workout of the user's, other than
+// excludeID, whose time span intersects [startedAt, completedAt), or nil.
+// Unfinished workouts span up to now; abandoned ones never overlap.
+func (q *WorkoutQueries) FindOverlappingWorkout(ctx context.Context, userID, excludeID int, startedAt, completedAt time.Time) (*models.WorkoutSession, error) {
+	query := `
+		SELECT ` + workoutColumns + `
+		FROM workout_sessions
+		WHERE user_id = $1
+			AND id <> $4
+			AND status <> 'abandoned'
+			AND started_at < $3
+			AND COALESCE(completed_at, NOW()) > $2
+		ORDER BY started_at ASC
+		LIMIT 1
+	`
+
+	workout, err := scanWorkout(q.db.QueryRowContext(ctx, query, userID, startedAt, completedAt, excludeID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return workout, nil
+}
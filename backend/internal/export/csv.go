@@ -0,0 +1,102 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+var csvHeader = []string{
+	"workout_id",
+	"workout_name",
+	"started_at",
+	"completed_at",
+	"exercise_name",
+	"exercise_definition_id",
+	"set_number",
+	"set_type",
+	"reps",
+	"weight",
+	"weight_unit",
+	"is_bodyweight",
+	"rpe",
+	"rir",
+	"tempo",
+	"rest_seconds",
+}
+
+// csvWriter writes Cadence's own flat layout: one row per set with every set
+// field, weights in the unit they were logged in.
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteWorkout(workout *models.WorkoutSession) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	completedAt := ""
+	if workout.CompletedAt != nil {
+		completedAt = workout.CompletedAt.UTC().Format(time.RFC3339)
+	}
+
+	for _, exercise := range workout.Exercises {
+		for _, set := range exercise.Sets {
+			tempo := ""
+			if set.Tempo != nil {
+				tempo = *set.Tempo
+			}
+
+			err := c.w.Write([]string{
+				strconv.Itoa(workout.ID),
+				workout.Name,
+				workout.StartedAt.UTC().Format(time.RFC3339),
+				completedAt,
+				exercise.Name,
+				strconv.Itoa(exercise.ExerciseDefinitionID),
+				strconv.Itoa(set.SetNumber),
+				set.SetType,
+				strconv.Itoa(set.Reps),
+				formatOptionalFloat(set.Weight),
+				set.WeightUnit,
+				strconv.FormatBool(set.IsBodyweight),
+				formatOptionalFloat(set.RPE),
+				formatOptionalInt(set.RIR),
+				tempo,
+				formatOptionalInt(set.RestSeconds),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// Close writes the header for an empty history so the file is still valid.
+func (c *csvWriter) Close() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+	c.headerWritten = true
+	return c.w.Write(csvHeader)
+}
@@ -0,0 +1,109 @@
+// Package export writes a user's workout history in downloadable formats,
+// one workout at a time so a full history never has to fit in memory.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatStrong = "strong"
+
+	DefaultFormat = FormatCSV
+)
+
+// Formats lists every export format, in the order they appear in archives.
+var Formats = []string{FormatCSV, FormatJSON, FormatStrong}
+
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatCSV, FormatJSON, FormatStrong:
+		return true
+	}
+	return false
+}
+
+func ContentType(format string) string {
+	if format == FormatJSON {
+		return "application/json"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// FileName is the name a download in format is saved under.
+func FileName(format string, date time.Time) string {
+	switch format {
+	case FormatJSON:
+		return fmt.Sprintf("cadence-export-%s.json", date.Format("2006-01-02"))
+	case FormatStrong:
+		return fmt.Sprintf("strong-export-%s.csv", date.Format("2006-01-02"))
+	}
+	return fmt.Sprintf("cadence-export-%s.csv", date.Format("2006-01-02"))
+}
+
+// Options controls presentation. Only the Strong layout uses them: it has no
+// unit or timezone columns, so weights and dates are written the way the user
+// sees them. The Cadence formats keep weights as entered and times in UTC.
+type Options struct {
+	Unit     string
+	Location *time.Location
+}
+
+// Writer receives completed workouts in order. Close must be called after the
+// last workout to finish the document.
+type Writer interface {
+	WriteWorkout(workout *models.WorkoutSession) error
+	Close() error
+}
+
+func NewWriter(w io.Writer, format string, opts Options) (Writer, error) {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	if opts.Unit == "" {
+		opts.Unit = units.DefaultUnit
+	}
+
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatJSON:
+		return newJSONWriter(w), nil
+	case FormatStrong:
+		return newStrongWriter(w, opts), nil
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return formatFloat(*value)
+}
+
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func formatOptionalString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,176 @@
+package export
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func floatPtr(v float64) *float64 { return &v }
+func intPtr(v int) *int           { return &v }
+func stringPtr(v string) *string  { return &v }
+
+// testWorkouts covers every set type, both units, bodyweight and unperformed
+// sets, and notes at each level.
+func testWorkouts() []*models.WorkoutSession {
+	push := time.Date(2024, 3, 4, 23, 30, 0, 0, time.UTC)
+	pushEnd := push.Add(65 * time.Minute)
+	legs := time.Date(2024, 3, 6, 12, 0, 0, 0, time.UTC)
+	legsEnd := legs.Add(45 * time.Minute)
+
+	set := func(number int, setType string, reps int, weight *float64, unit string) models.Set {
+		return models.Set{
+			SetNumber:   number,
+			SetType:     setType,
+			Reps:        reps,
+			Weight:      weight,
+			WeightUnit:  unit,
+			IsCompleted: true,
+		}
+	}
+
+	bench := []models.Set{
+		set(1, models.SetTypeWarmup, 10, floatPtr(95), units.Pounds),
+		set(2, models.SetTypeNormal, 5, floatPtr(225), units.Pounds),
+		set(3, models.SetTypeNormal, 5, floatPtr(227.5), units.Pounds),
+		set(4, models.SetTypeDrop, 8, floatPtr(185), units.Pounds),
+		set(5, models.SetTypeFailure, 6, floatPtr(185), units.Pounds),
+		set(6, models.SetTypeNormal, 5, floatPtr(225), units.Pounds),
+	}
+	bench[2].RPE = floatPtr(8.5)
+	bench[2].RIR = intPtr(1)
+	bench[2].Tempo = stringPtr("3-1-1-0")
+	bench[2].RestSeconds = intPtr(180)
+	bench[2].Notes = stringPtr("left shoulder tweaky")
+	bench[5].IsCompleted = false
+
+	dip := set(1, models.SetTypeNormal, 12, nil, units.Pounds)
+	dip.IsBodyweight = true
+
+	squat := []models.Set{
+		set(1, models.SetTypeNormal, 5, floatPtr(100), units.Kilograms),
+		set(2, models.SetTypeAMRAP, 9, floatPtr(101.25), units.Kilograms),
+	}
+
+	workouts := []*models.WorkoutSession{
+		{
+			ID:          1,
+			UserID:      7,
+			Name:        "Push, heavy",
+			Status:      models.WorkoutStatusCompleted,
+			StartedAt:   push,
+			CompletedAt: &pushEnd,
+			Notes:       stringPtr("Felt strong"),
+			Version:     1,
+			CreatedAt:   push,
+			UpdatedAt:   pushEnd,
+			Exercises: []models.Exercise{
+				{ID: 10, WorkoutSessionID: 1, ExerciseDefinitionID: 3, Name: "Bench Press", OrderIndex: 0, Notes: stringPtr("Pause reps"), Sets: bench},
+				{ID: 11, WorkoutSessionID: 1, ExerciseDefinitionID: 4, Name: "Dips", OrderIndex: 1, Sets: []models.Set{dip}},
+			},
+		},
+		{
+			ID:          2,
+			UserID:      7,
+			Name:        "Legs",
+			Status:      models.WorkoutStatusCompleted,
+			StartedAt:   legs,
+			CompletedAt: &legsEnd,
+			Version:     1,
+			CreatedAt:   legs,
+			UpdatedAt:   legsEnd,
+			Exercises: []models.Exercise{
+				{ID: 12, WorkoutSessionID: 2, ExerciseDefinitionID: 5, Name: "Squat", OrderIndex: 0, Sets: squat},
+			},
+		},
+	}
+
+	setID := 100
+	for _, workout := range workouts {
+		for i := range workout.Exercises {
+			exercise := &workout.Exercises[i]
+			exercise.Version = 1
+			exercise.CreatedAt, exercise.UpdatedAt = workout.StartedAt, workout.StartedAt
+			for j := range exercise.Sets {
+				setID++
+				exercise.Sets[j].ID = setID
+				exercise.Sets[j].ExerciseID = exercise.ID
+				exercise.Sets[j].CreatedAt, exercise.Sets[j].UpdatedAt = workout.StartedAt, workout.StartedAt
+			}
+		}
+	}
+
+	return workouts
+}
+
+func writeExport(t *testing.T, format string, workouts []*models.WorkoutSession) []byte {
+	t.Helper()
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format, Options{Unit: units.Pounds, Location: loc})
+	if err != nil {
+		t.Fatalf("NewWriter(%s): %v", format, err)
+	}
+	for _, workout := range workouts {
+		if err := w.WriteWorkout(workout); err != nil {
+			t.Fatalf("WriteWorkout: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// TestWriterGolden compares each format with testdata/<format>[-empty].golden.
+// Run with -update after an intended change to the output.
+func TestWriterGolden(t *testing.T) {
+	for _, format := range Formats {
+		for _, empty := range []bool{false, true} {
+			name := format
+			workouts := testWorkouts()
+			if empty {
+				name += "-empty"
+				workouts = nil
+			}
+
+			t.Run(name, func(t *testing.T) {
+				got := writeExport(t, format, workouts)
+				path := filepath.Join("testdata", name+".golden")
+
+				if *update {
+					if err := os.WriteFile(path, got, 0o644); err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				want, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Errorf("%s output differs from %s:\n%s", format, path, got)
+				}
+			})
+		}
+	}
+}
+
+func TestNewWriterRejectsUnknownFormat(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, "xlsx", Options{}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,46 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+// jsonWriter streams {"workouts": [...]} one element at a time.
+type jsonWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{w: w}
+}
+
+func (j *jsonWriter) WriteWorkout(workout *models.WorkoutSession) error {
+	prefix := ",\n"
+	if !j.started {
+		prefix = `{"workouts":[` + "\n"
+		j.started = true
+	}
+
+	data, err := json.Marshal(workout)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(j.w, prefix); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	if !j.started {
+		_, err := io.WriteString(j.w, `{"workouts":[]}`+"\n")
+		return err
+	}
+	_, err := io.WriteString(j.w, "\n]}\n")
+	return err
+}
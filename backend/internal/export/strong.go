@@ -0,0 +1,139 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+// StrongHeader is the column layout of the Strong app's CSV export, which
+// Hevy and most other trackers also import.
+var StrongHeader = []string{
+	"Date",
+	"Workout Name",
+	"Duration",
+	"Exercise Name",
+	"Set Order",
+	"Weight",
+	"Reps",
+	"Distance",
+	"Seconds",
+	"Notes",
+	"Workout Notes",
+	"RPE",
+}
+
+// StrongDateLayout is Strong's local wall-clock timestamp format.
+const StrongDateLayout = "2006-01-02 15:04:05"
+
+// strongSetOrders holds the letters Strong writes in Set Order for sets
+// other than working sets, which are numbered from 1 instead.
+var strongSetOrders = map[string]string{
+	models.SetTypeWarmup:  "W",
+	models.SetTypeDrop:    "D",
+	models.SetTypeFailure: "F",
+}
+
+type strongWriter struct {
+	w             *csv.Writer
+	opts          Options
+	headerWritten bool
+}
+
+func newStrongWriter(w io.Writer, opts Options) *strongWriter {
+	return &strongWriter{w: csv.NewWriter(w), opts: opts}
+}
+
+func (s *strongWriter) WriteWorkout(workout *models.WorkoutSession) error {
+	if err := s.writeHeader(); err != nil {
+		return err
+	}
+
+	date := workout.StartedAt.In(s.opts.Location).Format(StrongDateLayout)
+	duration := ""
+	if workout.CompletedAt != nil {
+		duration = formatStrongDuration(workout.CompletedAt.Sub(workout.StartedAt))
+	}
+	workoutNotes := formatOptionalString(workout.Notes)
+
+	for _, exercise := range workout.Exercises {
+		workingSets := 0
+		for _, set := range exercise.Sets {
+			// Strong only lists sets that were performed.
+			if !set.IsCompleted {
+				continue
+			}
+
+			order, ok := strongSetOrders[set.SetType]
+			if !ok {
+				workingSets++
+				order = strconv.Itoa(workingSets)
+			}
+
+			// Strong records bodyweight sets with a weight of zero.
+			weight := 0.0
+			if set.Weight != nil {
+				weight = units.ToPlate(*set.Weight, set.WeightUnit, s.opts.Unit)
+			}
+
+			// Strong only has notes per set, so exercise notes stand in for
+			// sets without their own.
+			notes := formatOptionalString(set.Notes)
+			if notes == "" {
+				notes = formatOptionalString(exercise.Notes)
+			}
+
+			err := s.w.Write([]string{
+				date,
+				workout.Name,
+				duration,
+				exercise.Name,
+				order,
+				formatFloat(weight),
+				strconv.Itoa(set.Reps),
+				"0",
+				"0",
+				notes,
+				workoutNotes,
+				formatOptionalFloat(set.RPE),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	s.w.Flush()
+	return s.w.Error()
+}
+
+func (s *strongWriter) Close() error {
+	if err := s.writeHeader(); err != nil {
+		return err
+	}
+	s.w.Flush()
+	return s.w.Error()
+}
+
+func (s *strongWriter) writeHeader() error {
+	if s.headerWritten {
+		return nil
+	}
+	s.headerWritten = true
+	return s.w.Write(StrongHeader)
+}
+
+// formatStrongDuration renders a duration the way Strong does: "1h 5m" or
+// "45m".
+func formatStrongDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+}
@@ -7,21 +7,21 @@ import (
 	"strings"
 
 	"github.com/damion-14/cadence/backend/internal/auth"
-	"github.com/damion-14/cadence/backend/internal/config"
 	"github.com/damion-14/cadence/backend/internal/database/queries"
 	"github.com/damion-14/cadence/backend/internal/middleware"
 	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/services"
 )
 
 type AuthHandler struct {
 	userQueries *queries.UserQueries
-	jwtConfig   config.JWTConfig
+	authService *services.AuthService
 }
 
-func NewAuthHandler(db *sql.DB, jwtConfig config.JWTConfig) *AuthHandler {
+func NewAuthHandler(db *sql.DB, authService *services.AuthService) *AuthHandler {
 	return &AuthHandler{
 		userQueries: queries.NewUserQueries(db),
-		jwtConfig:   jwtConfig,
+		authService: authService,
 	}
 }
 
@@ -61,15 +61,17 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := auth.GenerateToken(user.ID, user.Email, h.jwtConfig.Secret, h.jwtConfig.ExpiryHours)
+	tokens, err := h.authService.IssueTokens(r.Context(), user)
 	if err != nil {
 		respondError(w, r, models.ErrInternalServer)
 		return
 	}
 
 	respondJSON(w, http.StatusCreated, models.AuthResponse{
-		User:  *user,
-		Token: token,
+		User:         *user,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
 	})
 }
 
@@ -98,15 +100,92 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := auth.GenerateToken(user.ID, user.Email, h.jwtConfig.Secret, h.jwtConfig.ExpiryHours)
+	tokens, err := h.authService.IssueTokens(r.Context(), user)
 	if err != nil {
 		respondError(w, r, models.ErrInternalServer)
 		return
 	}
 
 	respondJSON(w, http.StatusOK, models.AuthResponse{
-		User:  *user,
-		Token: token,
+		User:         *user,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+	})
+}
+
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req models.RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	if req.RefreshToken == "" {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Refresh token is required", 400))
+		return
+	}
+
+	tokens, err := h.authService.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "expired") || strings.Contains(err.Error(), "reuse") {
+			respondError(w, r, models.NewAppError("UNAUTHORIZED", "Invalid or expired refresh token", 401))
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, tokens)
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	var req models.LogoutRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+			return
+		}
+	}
+
+	if err := h.authService.Logout(r.Context(), userID, middleware.GetTokenID(r.Context()), req.RefreshToken); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			respondError(w, r, models.ErrNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			respondError(w, r, models.ErrForbidden)
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.DeleteResponse{
+		Message: "Logged out successfully",
+	})
+}
+
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	if err := h.authService.LogoutAll(r.Context(), userID, middleware.GetTokenID(r.Context())); err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.DeleteResponse{
+		Message: "Logged out of all devices",
 	})
 }
 
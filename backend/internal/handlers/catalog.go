@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/damion-14/cadence/backend/internal/middleware"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/services"
+)
+
+var validUnitTypes = map[string]bool{
+	"weight_reps": true,
+	"reps":        true,
+	"duration":    true,
+	"distance":    true,
+}
+
+type CatalogHandler struct {
+	catalogService *services.CatalogService
+}
+
+func NewCatalogHandler(catalogService *services.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+	}
+}
+
+func (h *CatalogHandler) Search(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil {
+			limit = l
+		}
+	}
+
+	definitions, err := h.catalogService.Search(r.Context(), userID, r.URL.Query().Get("q"), limit)
+	if err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.CatalogResponse{
+		Definitions: definitions,
+	})
+}
+
+func (h *CatalogHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	definitionID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid exercise definition ID", 400))
+		return
+	}
+
+	definition, err := h.catalogService.GetDefinition(r.Context(), userID, definitionID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			respondError(w, r, models.ErrNotFound)
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.ExerciseDefinitionResponse{
+		Definition: *definition,
+	})
+}
+
+func (h *CatalogHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	var req models.CreateExerciseDefinitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Exercise name is required", 400))
+		return
+	}
+
+	if req.UnitType == "" {
+		req.UnitType = "weight_reps"
+	}
+	if !validUnitTypes[req.UnitType] {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Unit type must be one of weight_reps, reps, duration, distance", 400))
+		return
+	}
+
+	if req.DefaultRestSeconds != nil && (*req.DefaultRestSeconds < 0 || *req.DefaultRestSeconds > models.MaxRestSeconds) {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Default rest must be between 0 and 3600 seconds", 400))
+		return
+	}
+
+	req.Equipment = strings.ToLower(strings.TrimSpace(req.Equipment))
+	if req.Equipment == "" {
+		req.Equipment = "other"
+	}
+
+	// Aliases are matched against lowercased input, so store them that way.
+	aliases := []string{}
+	for _, alias := range req.Aliases {
+		if alias = strings.ToLower(strings.TrimSpace(alias)); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	req.Aliases = aliases
+
+	muscles := []string{}
+	for _, muscle := range req.PrimaryMuscles {
+		if muscle = strings.ToLower(strings.TrimSpace(muscle)); muscle != "" {
+			muscles = append(muscles, muscle)
+		}
+	}
+	req.PrimaryMuscles = muscles
+
+	definition, err := h.catalogService.CreateCustomDefinition(r.Context(), userID, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			respondError(w, r, models.NewAppError("CONFLICT", err.Error(), 409))
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, models.ExerciseDefinitionResponse{
+		Definition: *definition,
+	})
+}
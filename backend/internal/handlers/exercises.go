@@ -2,22 +2,31 @@ package handlers
 
 import (
 	"encoding/json"
+	"math"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/damion-14/cadence/backend/internal/middleware"
 	"github.com/damion-14/cadence/backend/internal/models"
 	"github.com/damion-14/cadence/backend/internal/services"
 )
 
+// tempoPattern matches eccentric, bottom pause, concentric and top pause
+// seconds, with X for an explosive phase.
+var tempoPattern = regexp.MustCompile(`^[0-9X]{4}$`)
+
 type ExerciseHandler struct {
 	workoutService *services.WorkoutService
+	userService    *services.UserService
 }
 
-func NewExerciseHandler(workoutService *services.WorkoutService) *ExerciseHandler {
+func NewExerciseHandler(workoutService *services.WorkoutService, userService *services.UserService) *ExerciseHandler {
 	return &ExerciseHandler{
 		workoutService: workoutService,
+		userService:    userService,
 	}
 }
 
@@ -41,8 +50,8 @@ func (h *ExerciseHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	req.Name = strings.TrimSpace(req.Name)
-	if req.Name == "" {
-		respondError(w, r, models.NewAppError("INVALID_INPUT", "Exercise name is required", 400))
+	if req.Name == "" && req.ExerciseDefinitionID == nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Exercise name or exercise_definition_id is required", 400))
 		return
 	}
 
@@ -51,21 +60,25 @@ func (h *ExerciseHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	for i, set := range req.Sets {
-		if set.Reps <= 0 {
-			respondError(w, r, models.NewAppError("INVALID_INPUT", "Reps must be greater than 0", 400))
-			return
-		}
-		if !set.IsBodyweight && (set.Weight == nil || *set.Weight <= 0) {
-			respondError(w, r, models.NewAppError("INVALID_INPUT", "Weight must be greater than 0 for non-bodyweight sets", 400))
+	if appErr := validateNotes(req.Notes); appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	for i := range req.Sets {
+		if appErr := validateSetInput(&req.Sets[i], unit); appErr != nil {
+			respondError(w, r, appErr)
 			return
 		}
-		if set.IsBodyweight {
-			req.Sets[i].Weight = nil
-		}
 	}
 
-	exercise, err := h.workoutService.AddExercise(r.Context(), userID, workoutID, req.Name, req.Sets)
+	exercise, err := h.workoutService.AddExercise(r.Context(), userID, workoutID, req.ExerciseDefinitionID, req.Name, req.Notes, req.Sets)
 	if err != nil {
 		if strings.Contains(err.Error(), "not found") {
 			respondError(w, r, models.ErrNotFound)
@@ -75,14 +88,15 @@ func (h *ExerciseHandler) Create(w http.ResponseWriter, r *http.Request) {
 			respondError(w, r, models.ErrForbidden)
 			return
 		}
-		if strings.Contains(err.Error(), "not active") {
-			respondError(w, r, models.NewAppError("INVALID_INPUT", "Workout is not active", 400))
+		if strings.Contains(err.Error(), "cannot be edited") {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Workout cannot be edited", 400))
 			return
 		}
 		respondError(w, r, models.ErrInternalServer)
 		return
 	}
 
+	convertExercise(exercise, unit)
 	respondJSON(w, http.StatusCreated, models.CreateExerciseResponse{
 		Exercise: *exercise,
 	})
@@ -107,6 +121,12 @@ func (h *ExerciseHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ifMatch, appErr := parseIfMatch(r)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
 	var req models.UpdateExerciseRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
@@ -119,24 +139,30 @@ func (h *ExerciseHandler) Update(w http.ResponseWriter, r *http.Request) {
 		namePtr = &req.Name
 	}
 
-	if len(req.Sets) > 0 {
-		for i, set := range req.Sets {
-			if set.Reps <= 0 {
-				respondError(w, r, models.NewAppError("INVALID_INPUT", "Reps must be greater than 0", 400))
-				return
-			}
-			if !set.IsBodyweight && (set.Weight == nil || *set.Weight <= 0) {
-				respondError(w, r, models.NewAppError("INVALID_INPUT", "Weight must be greater than 0 for non-bodyweight sets", 400))
-				return
-			}
-			if set.IsBodyweight {
-				req.Sets[i].Weight = nil
-			}
+	if appErr := validateNotes(req.Notes); appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	for i := range req.Sets {
+		if appErr := validateSetInput(&req.Sets[i], unit); appErr != nil {
+			respondError(w, r, appErr)
+			return
 		}
 	}
 
-	exercise, err := h.workoutService.UpdateExercise(r.Context(), userID, workoutID, exerciseID, namePtr, req.Sets)
+	exercise, err := h.workoutService.UpdateExercise(r.Context(), userID, workoutID, exerciseID, req.ExerciseDefinitionID, namePtr, req.Notes, req.Sets, ifMatch)
 	if err != nil {
+		if appErr := versionConflictError(w, err, unit); appErr != nil {
+			respondError(w, r, appErr)
+			return
+		}
 		if strings.Contains(err.Error(), "not found") {
 			respondError(w, r, models.ErrNotFound)
 			return
@@ -145,14 +171,16 @@ func (h *ExerciseHandler) Update(w http.ResponseWriter, r *http.Request) {
 			respondError(w, r, models.ErrForbidden)
 			return
 		}
-		if strings.Contains(err.Error(), "not active") {
-			respondError(w, r, models.NewAppError("INVALID_INPUT", "Workout is not active", 400))
+		if strings.Contains(err.Error(), "cannot be edited") {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Workout cannot be edited", 400))
 			return
 		}
 		respondError(w, r, models.ErrInternalServer)
 		return
 	}
 
+	convertExercise(exercise, unit)
+	w.Header().Set("ETag", etag(exercise.Version))
 	respondJSON(w, http.StatusOK, models.UpdateExerciseResponse{
 		Exercise: *exercise,
 	})
@@ -177,7 +205,23 @@ func (h *ExerciseHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.workoutService.DeleteExercise(r.Context(), userID, workoutID, exerciseID); err != nil {
+	ifMatch, appErr := parseIfMatch(r)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	if err := h.workoutService.DeleteExercise(r.Context(), userID, workoutID, exerciseID, ifMatch); err != nil {
+		if appErr := versionConflictError(w, err, unit); appErr != nil {
+			respondError(w, r, appErr)
+			return
+		}
 		if strings.Contains(err.Error(), "not found") {
 			respondError(w, r, models.ErrNotFound)
 			return
@@ -186,8 +230,8 @@ func (h *ExerciseHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			respondError(w, r, models.ErrForbidden)
 			return
 		}
-		if strings.Contains(err.Error(), "not active") {
-			respondError(w, r, models.NewAppError("INVALID_INPUT", "Workout is not active", 400))
+		if strings.Contains(err.Error(), "cannot be edited") {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Workout cannot be edited", 400))
 			return
 		}
 		respondError(w, r, models.ErrInternalServer)
@@ -198,3 +242,71 @@ func (h *ExerciseHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		Message: "Exercise deleted successfully",
 	})
 }
+
+// validateSetInput also fills in defaults: a missing weight unit becomes
+// unit and a missing set type becomes normal.
+func validateSetInput(set *models.SetInput, unit string) *models.AppError {
+	if set.Reps <= 0 {
+		return models.NewAppError("INVALID_INPUT", "Reps must be greater than 0", 400)
+	}
+	if !set.IsBodyweight && (set.Weight == nil || *set.Weight <= 0) {
+		return models.NewAppError("INVALID_INPUT", "Weight must be greater than 0 for non-bodyweight sets", 400)
+	}
+	if set.IsBodyweight {
+		set.Weight = nil
+	}
+	if appErr := normalizeWeightUnit(&set.WeightUnit, unit); appErr != nil {
+		return appErr
+	}
+
+	set.SetType = strings.ToLower(strings.TrimSpace(set.SetType))
+	if set.SetType == "" {
+		set.SetType = models.SetTypeNormal
+	}
+
+	if appErr := validateNotes(set.Notes); appErr != nil {
+		return appErr
+	}
+
+	return validateSetDetails(&set.SetType, set.RPE, set.RIR, set.Tempo, set.RestSeconds)
+}
+
+// validateNotes trims notes in place. An empty result is kept so updates can
+// clear existing notes.
+func validateNotes(notes *string) *models.AppError {
+	if notes == nil {
+		return nil
+	}
+
+	*notes = strings.TrimSpace(*notes)
+	if utf8.RuneCountInString(*notes) > models.MaxNotesLength {
+		return models.NewAppError("INVALID_INPUT", "Notes must be at most 2000 characters", 400)
+	}
+
+	return nil
+}
+
+// validateSetDetails checks the optional effort fields shared by full set
+// inputs and partial set updates. Nil values are left alone.
+func validateSetDetails(setType *string, rpe *float64, rir *int, tempo *string, restSeconds *int) *models.AppError {
+	if setType != nil && !models.IsValidSetType(*setType) {
+		return models.NewAppError("INVALID_INPUT", "Set type must be one of normal, warmup, drop, amrap, failure", 400)
+	}
+	if rpe != nil && (*rpe < 1 || *rpe > 10 || math.Mod(*rpe*2, 1) != 0) {
+		return models.NewAppError("INVALID_INPUT", "RPE must be between 1 and 10 in steps of 0.5", 400)
+	}
+	if rir != nil && (*rir < 0 || *rir > 10) {
+		return models.NewAppError("INVALID_INPUT", "RIR must be between 0 and 10", 400)
+	}
+	if tempo != nil {
+		*tempo = strings.ToUpper(strings.TrimSpace(*tempo))
+		if !tempoPattern.MatchString(*tempo) {
+			return models.NewAppError("INVALID_INPUT", "Tempo must be four digits or X, e.g. 3010 or 20X1", 400)
+		}
+	}
+	if restSeconds != nil && (*restSeconds < 0 || *restSeconds > models.MaxRestSeconds) {
+		return models.NewAppError("INVALID_INPUT", "Rest must be between 0 and 3600 seconds", 400)
+	}
+
+	return nil
+}
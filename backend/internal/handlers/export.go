@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/export"
+	"github.com/damion-14/cadence/backend/internal/middleware"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/services"
+)
+
+type ExportHandler struct {
+	exportService *services.ExportService
+	userService   *services.UserService
+}
+
+func NewExportHandler(exportService *services.ExportService, userService *services.UserService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		userService:   userService,
+	}
+}
+
+func (h *ExportHandler) Export(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format == "" {
+		format = export.DefaultFormat
+	}
+	if !export.IsValidFormat(format) {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Format must be one of csv, json, strong", 400))
+		return
+	}
+
+	opts, appErr := h.exportOptions(r, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	// A full history can take longer than the server's write timeout.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		fmt.Printf("Failed to clear write deadline for export: %v\n", err)
+	}
+
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.FileName(format, time.Now().In(opts.Location))))
+	w.WriteHeader(http.StatusOK)
+
+	// The status line is already sent, so a failure part way through can
+	// only be logged; the client sees a truncated download.
+	if err := h.exportService.Export(r.Context(), userID, format, w, opts); err != nil {
+		fmt.Printf("Failed to stream export: %v\n", err)
+	}
+}
+
+func (h *ExportHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	opts, appErr := h.exportOptions(r, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	job, err := h.exportService.StartExportJob(r.Context(), userID, opts)
+	if err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, models.ExportJobResponse{
+		Job: *job,
+	})
+}
+
+func (h *ExportHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	jobID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid export job ID", 400))
+		return
+	}
+
+	job, err := h.exportService.GetExportJob(r.Context(), userID, jobID)
+	if err != nil {
+		respondExportError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.ExportJobResponse{
+		Job: *job,
+	})
+}
+
+func (h *ExportHandler) DownloadJob(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	jobID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid export job ID", 400))
+		return
+	}
+
+	archive, job, err := h.exportService.OpenExportArchive(r.Context(), userID, jobID)
+	if err != nil {
+		respondExportError(w, r, err)
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		fmt.Printf("Failed to clear write deadline for export download: %v\n", err)
+	}
+
+	name := fmt.Sprintf("cadence-export-%s.zip", job.CreatedAt.Format("2006-01-02"))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, name))
+	http.ServeContent(w, r, name, *job.CompletedAt, archive)
+}
+
+// exportOptions captures how the Strong layout should present weights and
+// dates for this user.
+func (h *ExportHandler) exportOptions(r *http.Request, userID int) (export.Options, *models.AppError) {
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		return export.Options{}, appErr
+	}
+
+	return export.Options{
+		Unit:     unit,
+		Location: resolveLocation(r, h.userService, userID),
+	}, nil
+}
+
+func respondExportError(w http.ResponseWriter, r *http.Request, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		respondError(w, r, models.ErrNotFound)
+		return
+	}
+	if strings.Contains(err.Error(), "unauthorized") {
+		respondError(w, r, models.ErrForbidden)
+		return
+	}
+	if strings.Contains(err.Error(), "not ready") {
+		respondError(w, r, models.NewAppError("EXPORT_NOT_READY", "Export is not ready yet", 409))
+		return
+	}
+	if strings.Contains(err.Error(), "expired") {
+		respondError(w, r, models.NewAppError("EXPORT_EXPIRED", "Export has expired; start a new one", 410))
+		return
+	}
+	respondError(w, r, models.ErrInternalServer)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/importer"
+	"github.com/damion-14/cadence/backend/internal/middleware"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/services"
+)
+
+const (
+	maxImportBytes      = 20 << 20
+	importMemoryBytes   = 4 << 20
+	importReadDeadline  = 2 * time.Minute
+	importWriteDeadline = 5 * time.Minute
+)
+
+type ImportHandler struct {
+	importService *services.ImportService
+	userService   *services.UserService
+}
+
+func NewImportHandler(importService *services.ImportService, userService *services.UserService) *ImportHandler {
+	return &ImportHandler{
+		importService: importService,
+		userService:   userService,
+	}
+}
+
+// Import takes a multipart form with the export in "file". Optional fields:
+// "dry_run" (also accepted as a query parameter) and "mapping", a JSON object
+// from foreign exercise name to exercise_definition_id.
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	// Large exports can take longer to upload than the server's read timeout,
+	// and the write timeout runs from the same start, so the response needs
+	// room for the upload plus the import itself.
+	controller := http.NewResponseController(w)
+	if err := controller.SetReadDeadline(time.Now().Add(importReadDeadline)); err != nil {
+		fmt.Printf("Failed to extend read deadline for import: %v\n", err)
+	}
+	if err := controller.SetWriteDeadline(time.Now().Add(importWriteDeadline)); err != nil {
+		fmt.Printf("Failed to extend write deadline for import: %v\n", err)
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	if err := r.ParseMultipartForm(importMemoryBytes); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Expected a multipart form of at most 20 MB with the export in \"file\"", 400))
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "An export file is required in \"file\"", 400))
+		return
+	}
+	defer file.Close()
+
+	dryRun := false
+	if value := r.FormValue("dry_run"); value != "" {
+		dryRun, err = strconv.ParseBool(value)
+		if err != nil {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "dry_run must be true or false", 400))
+			return
+		}
+	}
+
+	overrides := map[string]int{}
+	if value := r.FormValue("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "mapping must be a JSON object of exercise name to exercise_definition_id", 400))
+			return
+		}
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	opts := importer.Options{
+		Unit:     unit,
+		Location: resolveLocation(r, h.userService, userID),
+	}
+
+	report, err := h.importService.Import(r.Context(), userID, file, opts, dryRun, overrides)
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid import file") || strings.Contains(err.Error(), "mapping for") {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", err.Error(), 400))
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	respondJSON(w, status, report)
+}
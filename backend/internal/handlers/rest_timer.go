@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/damion-14/cadence/backend/internal/middleware"
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+// GetRestTimer returns the rest timer running in the workout, with a null
+// timer when there is none.
+func (h *WorkoutHandler) GetRestTimer(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid workout ID", 400))
+		return
+	}
+
+	timer, err := h.workoutService.GetRestTimer(r.Context(), userID, workoutID)
+	if err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.RestTimerResponse{
+		Timer: timer,
+	})
+}
+
+func (h *WorkoutHandler) StartRestTimer(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid workout ID", 400))
+		return
+	}
+
+	var req models.StartRestTimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	if req.DurationSeconds != nil && (*req.DurationSeconds < 0 || *req.DurationSeconds > models.MaxRestSeconds) {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "duration_seconds must be between 0 and 3600", 400))
+		return
+	}
+
+	timer, err := h.workoutService.StartRestTimer(r.Context(), userID, workoutID, req.ExerciseID, req.DurationSeconds)
+	if err != nil {
+		respondError(w, r, restTimerError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, models.RestTimerResponse{
+		Timer: timer,
+	})
+}
+
+func (h *WorkoutHandler) ExtendRestTimer(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid workout ID", 400))
+		return
+	}
+
+	var req models.ExtendRestTimerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	if req.Seconds == 0 {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "seconds must not be zero", 400))
+		return
+	}
+
+	timer, err := h.workoutService.ExtendRestTimer(r.Context(), userID, workoutID, req.Seconds)
+	if err != nil {
+		respondError(w, r, restTimerError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.RestTimerResponse{
+		Timer: timer,
+	})
+}
+
+func (h *WorkoutHandler) StopRestTimer(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid workout ID", 400))
+		return
+	}
+
+	if err := h.workoutService.StopRestTimer(r.Context(), userID, workoutID); err != nil {
+		respondError(w, r, restTimerError(err))
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.DeleteResponse{
+		Message: "Rest timer stopped",
+	})
+}
+
+func restTimerError(err error) *models.AppError {
+	if strings.Contains(err.Error(), "not found") {
+		return models.ErrNotFound
+	}
+	if strings.Contains(err.Error(), "unauthorized") {
+		return models.ErrForbidden
+	}
+	if strings.Contains(err.Error(), "not active") || strings.Contains(err.Error(), "does not belong") {
+		return models.NewAppError("INVALID_INPUT", err.Error(), 400)
+	}
+	return models.ErrInternalServer
+}
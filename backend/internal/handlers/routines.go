@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/damion-14/cadence/backend/internal/middleware"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/services"
+)
+
+type RoutineHandler struct {
+	routineService *services.RoutineService
+	userService    *services.UserService
+}
+
+func NewRoutineHandler(routineService *services.RoutineService, userService *services.UserService) *RoutineHandler {
+	return &RoutineHandler{
+		routineService: routineService,
+		userService:    userService,
+	}
+}
+
+func (h *RoutineHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	routines, err := h.routineService.ListRoutines(r.Context(), userID)
+	if err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	for i := range routines {
+		convertRoutine(&routines[i], unit)
+	}
+	respondJSON(w, http.StatusOK, models.RoutinesResponse{
+		Routines: routines,
+	})
+}
+
+func (h *RoutineHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	routineID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid routine ID", 400))
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	routine, err := h.routineService.GetRoutine(r.Context(), userID, routineID)
+	if err != nil {
+		respondRoutineError(w, r, err)
+		return
+	}
+
+	convertRoutine(routine, unit)
+	respondJSON(w, http.StatusOK, models.RoutineResponse{
+		Routine: *routine,
+	})
+}
+
+func (h *RoutineHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	var req models.RoutineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	if appErr := validateRoutineRequest(&req, unit); appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	routine, err := h.routineService.CreateRoutine(r.Context(), userID, req.Name, req.Exercises)
+	if err != nil {
+		respondRoutineError(w, r, err)
+		return
+	}
+
+	convertRoutine(routine, unit)
+	respondJSON(w, http.StatusCreated, models.RoutineResponse{
+		Routine: *routine,
+	})
+}
+
+func (h *RoutineHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	routineID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid routine ID", 400))
+		return
+	}
+
+	var req models.RoutineRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	if appErr := validateRoutineRequest(&req, unit); appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	routine, err := h.routineService.UpdateRoutine(r.Context(), userID, routineID, req.Name, req.Exercises)
+	if err != nil {
+		respondRoutineError(w, r, err)
+		return
+	}
+
+	convertRoutine(routine, unit)
+	respondJSON(w, http.StatusOK, models.RoutineResponse{
+		Routine: *routine,
+	})
+}
+
+func (h *RoutineHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	routineID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid routine ID", 400))
+		return
+	}
+
+	if err := h.routineService.DeleteRoutine(r.Context(), userID, routineID); err != nil {
+		respondRoutineError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.DeleteResponse{
+		Message: "Routine deleted successfully",
+	})
+}
+
+func (h *RoutineHandler) SaveFromWorkout(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid workout ID", 400))
+		return
+	}
+
+	var req models.SaveAsRoutineRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+			return
+		}
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	routine, err := h.routineService.CreateRoutineFromWorkout(r.Context(), userID, workoutID, strings.TrimSpace(req.Name))
+	if err != nil {
+		if strings.Contains(err.Error(), "not completed") || strings.Contains(err.Error(), "no exercises") || strings.Contains(err.Error(), "name is required") {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", err.Error(), 400))
+			return
+		}
+		respondRoutineError(w, r, err)
+		return
+	}
+
+	convertRoutine(routine, unit)
+	respondJSON(w, http.StatusCreated, models.RoutineResponse{
+		Routine: *routine,
+	})
+}
+
+func validateRoutineRequest(req *models.RoutineRequest, unit string) *models.AppError {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return models.NewAppError("INVALID_INPUT", "Routine name is required", 400)
+	}
+
+	if len(req.Exercises) == 0 {
+		return models.NewAppError("INVALID_INPUT", "At least one exercise is required", 400)
+	}
+
+	for i, exercise := range req.Exercises {
+		req.Exercises[i].Name = strings.TrimSpace(exercise.Name)
+		if req.Exercises[i].Name == "" && exercise.ExerciseDefinitionID == nil {
+			return models.NewAppError("INVALID_INPUT", "Exercise name or exercise_definition_id is required", 400)
+		}
+		if exercise.TargetSets <= 0 {
+			return models.NewAppError("INVALID_INPUT", "Target sets must be greater than 0", 400)
+		}
+		if exercise.TargetReps <= 0 {
+			return models.NewAppError("INVALID_INPUT", "Target reps must be greater than 0", 400)
+		}
+		if exercise.TargetWeight != nil && *exercise.TargetWeight < 0 {
+			return models.NewAppError("INVALID_INPUT", "Target weight cannot be negative", 400)
+		}
+		if exercise.IsBodyweight {
+			req.Exercises[i].TargetWeight = nil
+		}
+		if appErr := normalizeWeightUnit(&req.Exercises[i].WeightUnit, unit); appErr != nil {
+			return appErr
+		}
+	}
+
+	return nil
+}
+
+func respondRoutineError(w http.ResponseWriter, r *http.Request, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		respondError(w, r, models.ErrNotFound)
+		return
+	}
+	if strings.Contains(err.Error(), "unauthorized") {
+		respondError(w, r, models.ErrForbidden)
+		return
+	}
+	respondError(w, r, models.ErrInternalServer)
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/damion-14/cadence/backend/internal/middleware"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/services"
+)
+
+const maxSearchLength = 200
+
+type SearchHandler struct {
+	searchService *services.SearchService
+}
+
+func NewSearchHandler(searchService *services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search takes q in web search syntax: plain words, "quoted phrases", OR
+// between alternatives and -word to exclude a word.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	search := strings.TrimSpace(r.URL.Query().Get("q"))
+	if search == "" {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Search query is required", 400))
+		return
+	}
+	if len(search) > maxSearchLength {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Search query must be at most 200 characters", 400))
+		return
+	}
+
+	limit := 20
+	offset := 0
+
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+			limit = l
+		}
+	}
+
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	results, err := h.searchService.Search(r.Context(), userID, search, limit, offset)
+	if err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.SearchResponse{
+		Query:   search,
+		Results: results,
+		Limit:   limit,
+		Offset:  offset,
+	})
+}
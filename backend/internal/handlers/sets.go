@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/damion-14/cadence/backend/internal/middleware"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/services"
+)
+
+type SetHandler struct {
+	workoutService *services.WorkoutService
+	userService    *services.UserService
+}
+
+func NewSetHandler(workoutService *services.WorkoutService, userService *services.UserService) *SetHandler {
+	return &SetHandler{
+		workoutService: workoutService,
+		userService:    userService,
+	}
+}
+
+func (h *SetHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, exerciseID, ok := parseExercisePath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	if appErr := validateSetInput(&req.SetInput, unit); appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	if req.Position != nil && *req.Position <= 0 {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Position must be greater than 0", 400))
+		return
+	}
+
+	set, err := h.workoutService.AddSet(r.Context(), userID, workoutID, exerciseID, req.SetInput, req.Position)
+	if err != nil {
+		respondSetError(w, r, err)
+		return
+	}
+
+	convertSet(set, unit)
+	respondJSON(w, http.StatusCreated, models.SetResponse{
+		Set: *set,
+	})
+}
+
+func (h *SetHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, exerciseID, ok := parseExercisePath(w, r)
+	if !ok {
+		return
+	}
+
+	setID, err := strconv.Atoi(r.PathValue("setId"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid set ID", 400))
+		return
+	}
+
+	var req models.UpdateSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	if req.Reps != nil && *req.Reps <= 0 {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Reps must be greater than 0", 400))
+		return
+	}
+	if req.Weight != nil && *req.Weight <= 0 {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Weight must be greater than 0", 400))
+		return
+	}
+	if req.SetNumber != nil && *req.SetNumber <= 0 {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Set number must be greater than 0", 400))
+		return
+	}
+	if req.SetType != nil {
+		*req.SetType = strings.ToLower(strings.TrimSpace(*req.SetType))
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	// A new weight without a unit is taken to be in the display unit.
+	if req.Weight != nil && req.WeightUnit == nil {
+		req.WeightUnit = &unit
+	}
+	if req.WeightUnit != nil {
+		if appErr := normalizeWeightUnit(req.WeightUnit, unit); appErr != nil {
+			respondError(w, r, appErr)
+			return
+		}
+	}
+	if appErr := validateSetDetails(req.SetType, req.RPE, req.RIR, req.Tempo, req.RestSeconds); appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+	if appErr := validateNotes(req.Notes); appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	set, err := h.workoutService.UpdateSet(r.Context(), userID, workoutID, exerciseID, setID, req)
+	if err != nil {
+		respondSetError(w, r, err)
+		return
+	}
+
+	convertSet(set, unit)
+	respondJSON(w, http.StatusOK, models.SetResponse{
+		Set: *set,
+	})
+}
+
+func (h *SetHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, exerciseID, ok := parseExercisePath(w, r)
+	if !ok {
+		return
+	}
+
+	setID, err := strconv.Atoi(r.PathValue("setId"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid set ID", 400))
+		return
+	}
+
+	if err := h.workoutService.DeleteSet(r.Context(), userID, workoutID, exerciseID, setID); err != nil {
+		respondSetError(w, r, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.DeleteResponse{
+		Message: "Set deleted successfully",
+	})
+}
+
+func (h *SetHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, exerciseID, ok := parseExercisePath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.ReorderSetsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	exercise, err := h.workoutService.ReorderSets(r.Context(), userID, workoutID, exerciseID, req.SetIDs)
+	if err != nil {
+		respondSetError(w, r, err)
+		return
+	}
+
+	convertExercise(exercise, unit)
+	respondJSON(w, http.StatusOK, models.UpdateExerciseResponse{
+		Exercise: *exercise,
+	})
+}
+
+func parseExercisePath(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	workoutID, err := strconv.Atoi(r.PathValue("workoutId"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid workout ID", 400))
+		return 0, 0, false
+	}
+
+	exerciseID, err := strconv.Atoi(r.PathValue("exerciseId"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid exercise ID", 400))
+		return 0, 0, false
+	}
+
+	return workoutID, exerciseID, true
+}
+
+func respondSetError(w http.ResponseWriter, r *http.Request, err error) {
+	if strings.Contains(err.Error(), "not found") {
+		respondError(w, r, models.ErrNotFound)
+		return
+	}
+	if strings.Contains(err.Error(), "unauthorized") || strings.Contains(err.Error(), "does not belong") {
+		respondError(w, r, models.ErrForbidden)
+		return
+	}
+	if strings.Contains(err.Error(), "cannot be edited") {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Workout cannot be edited", 400))
+		return
+	}
+	if strings.Contains(err.Error(), "is required") || strings.Contains(err.Error(), "exactly once") {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", err.Error(), 400))
+		return
+	}
+	respondError(w, r, models.ErrInternalServer)
+}
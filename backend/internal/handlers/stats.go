@@ -1,21 +1,30 @@
 package handlers
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/damion-14/cadence/backend/internal/middleware"
 	"github.com/damion-14/cadence/backend/internal/models"
 	"github.com/damion-14/cadence/backend/internal/services"
+	"github.com/damion-14/cadence/backend/internal/units"
 )
 
 type StatsHandler struct {
 	statsService *services.StatsService
+	userService  *services.UserService
 }
 
-func NewStatsHandler(statsService *services.StatsService) *StatsHandler {
+func NewStatsHandler(statsService *services.StatsService, userService *services.UserService) *StatsHandler {
 	return &StatsHandler{
 		statsService: statsService,
+		userService:  userService,
 	}
 }
 
@@ -26,29 +35,42 @@ func (h *StatsHandler) GetPRs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	prs, err := h.statsService.GetPersonalRecords(r.Context(), userID)
+	formula, ok := parseE1RMFormula(w, r)
+	if !ok {
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	prs, err := h.statsService.GetPersonalRecords(r.Context(), userID, formula)
 	if err != nil {
 		respondError(w, r, models.ErrInternalServer)
 		return
 	}
 
+	convertPersonalRecords(prs, unit)
 	respondJSON(w, http.StatusOK, models.PRsResponse{
-		PRs: prs,
+		PRs:  prs,
+		Unit: unit,
 	})
 }
 
-func (h *StatsHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+func (h *StatsHandler) GetPRTimeline(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == 0 {
 		respondError(w, r, models.ErrUnauthorized)
 		return
 	}
 
-	limit := 20
+	limit := 50
 	offset := 0
 
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 200 {
 			limit = l
 		}
 	}
@@ -59,18 +81,173 @@ func (h *StatsHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	workouts, total, err := h.statsService.GetWorkoutHistory(r.Context(), userID, limit, offset)
+	var definitionID *int
+	if idStr := r.URL.Query().Get("exercise_definition_id"); idStr != "" {
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid exercise_definition_id", 400))
+			return
+		}
+		definitionID = &id
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	events, total, err := h.statsService.GetPRTimeline(r.Context(), userID, definitionID, limit, offset)
+	if err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	convertPREvents(events, unit)
+	respondJSON(w, http.StatusOK, models.PRTimelineResponse{
+		Events: events,
+		Total:  total,
+		Unit:   unit,
+	})
+}
+
+// GetHistory pages through completed workouts, newest first. Pass the
+// previous page's next_cursor as ?cursor= for the next one. Optional filters:
+// from and to (YYYY-MM-DD in the user's timezone, both inclusive), name
+// (substring of the workout name), exercise (name or alias) or
+// exercise_definition_id, and min_volume (in the response unit).
+func (h *StatsHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+
+	limit := 20
+	if limitStr := query.Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l <= 0 || l > 100 {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "limit must be between 1 and 100", 400))
+			return
+		}
+		limit = l
+	}
+
+	var cursor *models.HistoryCursor
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		var err error
+		cursor, err = decodeHistoryCursor(cursorStr)
+		if err != nil {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid cursor", 400))
+			return
+		}
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	loc := resolveLocation(r, h.userService, userID)
+
+	filter := models.HistoryFilter{
+		Name: strings.TrimSpace(query.Get("name")),
+	}
+
+	if from := query.Get("from"); from != "" {
+		day, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "from must be a date (YYYY-MM-DD)", 400))
+			return
+		}
+		filter.From = &day
+	}
+
+	if to := query.Get("to"); to != "" {
+		day, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "to must be a date (YYYY-MM-DD)", 400))
+			return
+		}
+		end := day.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "from must not be after to", 400))
+		return
+	}
+
+	exerciseName := strings.TrimSpace(query.Get("exercise"))
+	if idStr := query.Get("exercise_definition_id"); idStr != "" {
+		if exerciseName != "" {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Use either exercise or exercise_definition_id, not both", 400))
+			return
+		}
+		id, err := strconv.Atoi(idStr)
+		if err != nil || id <= 0 {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid exercise_definition_id", 400))
+			return
+		}
+		filter.ExerciseDefinitionID = &id
+	}
+
+	if volumeStr := query.Get("min_volume"); volumeStr != "" {
+		volume, err := strconv.ParseFloat(volumeStr, 64)
+		if err != nil || volume < 0 || math.IsNaN(volume) || math.IsInf(volume, 0) {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "min_volume must be a non-negative number", 400))
+			return
+		}
+		volume = units.Convert(volume, unit, units.Kilograms)
+		filter.MinVolume = &volume
+	}
+
+	workouts, next, err := h.statsService.GetWorkoutHistory(r.Context(), userID, filter, exerciseName, cursor, limit)
 	if err != nil {
 		respondError(w, r, models.ErrInternalServer)
 		return
 	}
 
+	var nextCursor *string
+	if next != nil {
+		encoded := encodeHistoryCursor(next)
+		nextCursor = &encoded
+	}
+
+	convertHistory(workouts, unit)
 	respondJSON(w, http.StatusOK, models.HistoryResponse{
-		Workouts: workouts,
-		Total:    total,
+		Workouts:   workouts,
+		NextCursor: nextCursor,
+		Unit:       unit,
 	})
 }
 
+func encodeHistoryCursor(cursor *models.HistoryCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeHistoryCursor(value string) (*models.HistoryCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor models.HistoryCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+
+	if cursor.ID <= 0 || cursor.CompletedAt.IsZero() {
+		return nil, fmt.Errorf("incomplete cursor")
+	}
+
+	return &cursor, nil
+}
+
 func (h *StatsHandler) GetWeeklySummary(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == 0 {
@@ -80,12 +257,21 @@ func (h *StatsHandler) GetWeeklySummary(w http.ResponseWriter, r *http.Request)
 
 	week := r.URL.Query().Get("week")
 
-	summary, err := h.statsService.GetWeeklySummary(r.Context(), userID, week)
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	loc := resolveLocation(r, h.userService, userID)
+
+	summary, err := h.statsService.GetWeeklySummary(r.Context(), userID, week, loc)
 	if err != nil {
 		respondError(w, r, models.NewAppError("INVALID_INPUT", err.Error(), 400))
 		return
 	}
 
+	convertWeeklySummary(summary, unit)
 	respondJSON(w, http.StatusOK, summary)
 }
 
@@ -104,14 +290,74 @@ func (h *StatsHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
 
 	period := r.URL.Query().Get("period")
 
-	dataPoints, err := h.statsService.GetExerciseProgress(r.Context(), userID, exerciseName, period)
+	formula, ok := parseE1RMFormula(w, r)
+	if !ok {
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	loc := resolveLocation(r, h.userService, userID)
+
+	definition, dataPoints, err := h.statsService.GetExerciseProgress(r.Context(), userID, exerciseName, period, formula, loc)
 	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			respondJSON(w, http.StatusOK, models.ProgressResponse{
+				ExerciseName: exerciseName,
+				Formula:      formula,
+				Unit:         unit,
+				DataPoints:   []models.ProgressDataPoint{},
+			})
+			return
+		}
 		respondError(w, r, models.ErrInternalServer)
 		return
 	}
 
+	convertProgress(dataPoints, unit)
 	respondJSON(w, http.StatusOK, models.ProgressResponse{
-		ExerciseName: exerciseName,
-		DataPoints:   dataPoints,
+		ExerciseDefinitionID: definition.ID,
+		ExerciseName:         definition.Name,
+		Formula:              formula,
+		Unit:                 unit,
+		DataPoints:           dataPoints,
+	})
+}
+
+// GetRest reports the average rest taken before each exercise's working sets.
+func (h *StatsHandler) GetRest(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	days, exercises, err := h.statsService.GetAverageRest(r.Context(), userID, r.URL.Query().Get("period"))
+	if err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.RestStatsResponse{
+		Days:      days,
+		Exercises: exercises,
 	})
 }
+
+func parseE1RMFormula(w http.ResponseWriter, r *http.Request) (string, bool) {
+	formula := strings.ToLower(r.URL.Query().Get("formula"))
+	if formula == "" {
+		return models.DefaultE1RMFormula, true
+	}
+
+	if !models.IsValidE1RMFormula(formula) {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Formula must be one of epley, brzycki, lombardi", 400))
+		return "", false
+	}
+
+	return formula, true
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+func TestHistoryCursorRoundTrip(t *testing.T) {
+	cursor := &models.HistoryCursor{
+		CompletedAt: time.Date(2024, 3, 4, 18, 30, 15, 123456000, time.FixedZone("EST", -5*60*60)),
+		ID:          42,
+	}
+
+	encoded := encodeHistoryCursor(cursor)
+	decoded, err := decodeHistoryCursor(encoded)
+	if err != nil {
+		t.Fatalf("decodeHistoryCursor(%q): %v", encoded, err)
+	}
+
+	if decoded.ID != cursor.ID || !decoded.CompletedAt.Equal(cursor.CompletedAt) {
+		t.Fatalf("round trip = %+v, want %+v", decoded, cursor)
+	}
+}
+
+func TestDecodeHistoryCursorRejects(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"not base64", "not*base64"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"completed_at":"2024-03-04T18:30:00Z","id":1}`))},
+		{"not json", encode("cursor")},
+		{"missing id", encode(`{"completed_at":"2024-03-04T18:30:00Z"}`)},
+		{"zero id", encode(`{"completed_at":"2024-03-04T18:30:00Z","id":0}`)},
+		{"negative id", encode(`{"completed_at":"2024-03-04T18:30:00Z","id":-3}`)},
+		{"missing time", encode(`{"id":7}`)},
+		{"bad time", encode(`{"completed_at":"yesterday","id":7}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if cursor, err := decodeHistoryCursor(tt.value); err == nil {
+				t.Fatalf("decodeHistoryCursor(%q) = %+v, want an error", tt.value, cursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/services"
+)
+
+// resolveLocation loads the user's timezone for day and week bucketing.
+// Lookup failures fall back to UTC rather than failing the request.
+func resolveLocation(r *http.Request, userService *services.UserService, userID int) *time.Location {
+	prefs, err := userService.GetPreferences(r.Context(), userID)
+	if err != nil {
+		fmt.Printf("Failed to load user preferences: %v\n", err)
+		return time.UTC
+	}
+
+	if prefs.Timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(prefs.Timezone)
+	if err != nil {
+		fmt.Printf("Failed to load timezone %q: %v\n", prefs.Timezone, err)
+		return time.UTC
+	}
+
+	return loc
+}
+
+// isValidTimezone accepts IANA zone names only; "Local" would silently mean
+// whatever zone the server runs in.
+func isValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
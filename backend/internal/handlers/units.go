@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/services"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+// resolveUnit picks the weight unit for a request: an explicit ?unit= wins,
+// then the user's preference. A failed preference lookup falls back to the
+// default rather than failing the request.
+func resolveUnit(r *http.Request, userService *services.UserService, userID int) (string, *models.AppError) {
+	if unit := strings.ToLower(r.URL.Query().Get("unit")); unit != "" {
+		if !units.IsValid(unit) {
+			return "", models.NewAppError("INVALID_INPUT", "Unit must be kg or lb", 400)
+		}
+		return unit, nil
+	}
+
+	prefs, err := userService.GetPreferences(r.Context(), userID)
+	if err != nil {
+		fmt.Printf("Failed to load user preferences: %v\n", err)
+		return units.DefaultUnit, nil
+	}
+
+	return prefs.PreferredUnit, nil
+}
+
+// normalizeWeightUnit lowercases an entered unit, defaulting it to unit when
+// the client left it out.
+func normalizeWeightUnit(weightUnit *string, unit string) *models.AppError {
+	*weightUnit = strings.ToLower(strings.TrimSpace(*weightUnit))
+	if *weightUnit == "" {
+		*weightUnit = unit
+	}
+	if !units.IsValid(*weightUnit) {
+		return models.NewAppError("INVALID_INPUT", "Weight unit must be kg or lb", 400)
+	}
+	return nil
+}
+
+// Sets keep the unit they were entered in; only weights in the other unit
+// are converted and rounded to a loadable weight.
+func convertSet(set *models.Set, unit string) {
+	if set.Weight != nil {
+		weight := units.ToPlate(*set.Weight, set.WeightUnit, unit)
+		set.Weight = &weight
+	}
+	set.WeightUnit = unit
+}
+
+func convertExercise(exercise *models.Exercise, unit string) {
+	for i := range exercise.Sets {
+		convertSet(&exercise.Sets[i], unit)
+	}
+}
+
+func convertWorkout(workout *models.WorkoutSession, unit string) {
+	for i := range workout.Exercises {
+		convertExercise(&workout.Exercises[i], unit)
+	}
+}
+
+func convertRoutine(routine *models.Routine, unit string) {
+	for i := range routine.Exercises {
+		exercise := &routine.Exercises[i]
+		if exercise.TargetWeight != nil {
+			weight := units.ToPlate(*exercise.TargetWeight, exercise.WeightUnit, unit)
+			exercise.TargetWeight = &weight
+		}
+		exercise.WeightUnit = unit
+	}
+}
+
+// Stats figures come back from the queries in kilograms, except set weights,
+// which carry the unit they were entered in.
+
+func convertWeight(kg float64, unit string) float64 {
+	return units.RoundToPlate(units.Convert(kg, units.Kilograms, unit), unit)
+}
+
+// convertEnteredWeight converts a set weight like convertSet does. Without a
+// unit, as in figures cached before units were recorded, it is kilograms.
+func convertEnteredWeight(weight float64, weightUnit, unit string) float64 {
+	if weightUnit == "" {
+		return convertWeight(weight, unit)
+	}
+	return units.ToPlate(weight, weightUnit, unit)
+}
+
+func convertDerived(kg float64, unit string) float64 {
+	return units.ToDisplay(kg, units.Kilograms, unit)
+}
+
+func convertRecordValue(category string, value float64, unit string) float64 {
+	switch category {
+	case models.PRCategoryMostReps:
+		return value
+	case models.PRCategoryHeaviestSingle, models.PRCategoryRepMax:
+		return convertWeight(value, unit)
+	}
+	return convertDerived(value, unit)
+}
+
+// convertPRValue converts one side of a PR event, preferring the weight as it
+// was entered when the event recorded one.
+func convertPRValue(category string, value float64, weight *float64, weightUnit *string, unit string) float64 {
+	if weight != nil && weightUnit != nil {
+		return convertEnteredWeight(*weight, *weightUnit, unit)
+	}
+	return convertRecordValue(category, value, unit)
+}
+
+func convertRecord(record *models.Record, unit string) {
+	if record == nil {
+		return
+	}
+	if record.Weight != nil {
+		weight := convertEnteredWeight(*record.Weight, record.WeightUnit, unit)
+		record.Weight = &weight
+		record.WeightUnit = unit
+	}
+	switch record.Category {
+	case models.PRCategoryHeaviestSingle, models.PRCategoryRepMax:
+		if record.Weight != nil {
+			record.Value = *record.Weight
+			return
+		}
+	}
+	record.Value = convertRecordValue(record.Category, record.Value, unit)
+}
+
+func convertPersonalRecords(prs []models.PersonalRecord, unit string) {
+	for i := range prs {
+		convertRecord(prs[i].HeaviestSingle, unit)
+		convertRecord(prs[i].BestE1RM, unit)
+		convertRecord(prs[i].MostReps, unit)
+		convertRecord(prs[i].BestSessionVolume, unit)
+		for j := range prs[i].RepMaxes {
+			convertRecord(&prs[i].RepMaxes[j], unit)
+		}
+	}
+}
+
+func convertPREvents(events []models.PREvent, unit string) {
+	for i := range events {
+		event := &events[i]
+		event.OldValue = convertPRValue(event.Category, event.OldValue, event.OldWeight, event.OldWeightUnit, unit)
+		event.NewValue = convertPRValue(event.Category, event.NewValue, event.NewWeight, event.NewWeightUnit, unit)
+	}
+}
+
+func convertProgress(points []models.ProgressDataPoint, unit string) {
+	for i := range points {
+		if points[i].MaxWeight != nil {
+			weight := convertEnteredWeight(*points[i].MaxWeight, points[i].MaxWeightUnit, unit)
+			points[i].MaxWeight = &weight
+			points[i].MaxWeightUnit = unit
+		}
+		if points[i].E1RM != nil {
+			e1rm := convertDerived(*points[i].E1RM, unit)
+			points[i].E1RM = &e1rm
+		}
+		points[i].Volume = convertDerived(points[i].Volume, unit)
+	}
+}
+
+func convertHistory(workouts []models.WorkoutSummary, unit string) {
+	for i := range workouts {
+		workouts[i].TotalVolume = convertDerived(workouts[i].TotalVolume, unit)
+	}
+}
+
+func convertWeeklySummary(summary *models.WeeklySummary, unit string) {
+	summary.TotalVolume = convertDerived(summary.TotalVolume, unit)
+	summary.Unit = unit
+}
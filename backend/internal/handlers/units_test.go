@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func TestResolveUnitQueryParameter(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{"?unit=kg", units.Kilograms, false},
+		{"?unit=LB", units.Pounds, false},
+		{"?unit=stone", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			// An explicit unit never consults the user service.
+			got, appErr := resolveUnit(httptest.NewRequest("GET", "/"+tt.query, nil), nil, 1)
+			if (appErr != nil) != tt.wantErr {
+				t.Fatalf("appErr = %v, wantErr %v", appErr, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("unit = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeWeightUnit(t *testing.T) {
+	tests := []struct {
+		entered string
+		want    string
+		wantErr bool
+	}{
+		{"", units.Pounds, false},
+		{" KG ", units.Kilograms, false},
+		{"lb", units.Pounds, false},
+		{"lbs", "lbs", true},
+	}
+
+	for _, tt := range tests {
+		weightUnit := tt.entered
+		appErr := normalizeWeightUnit(&weightUnit, units.Pounds)
+		if (appErr != nil) != tt.wantErr {
+			t.Errorf("normalizeWeightUnit(%q) appErr = %v, wantErr %v", tt.entered, appErr, tt.wantErr)
+		}
+		if weightUnit != tt.want {
+			t.Errorf("normalizeWeightUnit(%q) = %q, want %q", tt.entered, weightUnit, tt.want)
+		}
+	}
+}
+
+func TestConvertWorkoutSets(t *testing.T) {
+	workout := &models.WorkoutSession{
+		Exercises: []models.Exercise{{
+			Sets: []models.Set{
+				{Weight: floatPtr(100), WeightUnit: units.Kilograms},
+				{Weight: floatPtr(225), WeightUnit: units.Pounds},
+				{Weight: nil, WeightUnit: units.Kilograms, IsBodyweight: true},
+			},
+		}},
+	}
+
+	convertWorkout(workout, units.Pounds)
+
+	sets := workout.Exercises[0].Sets
+	if *sets[0].Weight != 220 || sets[0].WeightUnit != units.Pounds {
+		t.Errorf("kg set = %v %s, want 220 lb", *sets[0].Weight, sets[0].WeightUnit)
+	}
+	if *sets[1].Weight != 225 || sets[1].WeightUnit != units.Pounds {
+		t.Errorf("lb set = %v %s, want 225 lb unchanged", *sets[1].Weight, sets[1].WeightUnit)
+	}
+	if sets[2].Weight != nil || sets[2].WeightUnit != units.Pounds {
+		t.Errorf("bodyweight set = %v %s, want no weight in lb", sets[2].Weight, sets[2].WeightUnit)
+	}
+}
+
+func TestConvertRecordValue(t *testing.T) {
+	tests := []struct {
+		category string
+		kg       float64
+		unit     string
+		want     float64
+	}{
+		{models.PRCategoryMostReps, 20, units.Pounds, 20},
+		{models.PRCategoryHeaviestSingle, 100, units.Pounds, 220},
+		{models.PRCategoryRepMax, 100, units.Kilograms, 100},
+		{models.PRCategoryE1RM, 100, units.Pounds, 220.5},
+		{models.PRCategorySessionVolume, 5000, units.Pounds, 11023.1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			if got := convertRecordValue(tt.category, tt.kg, tt.unit); got != tt.want {
+				t.Fatalf("convertRecordValue(%s, %v, %s) = %v, want %v", tt.category, tt.kg, tt.unit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertProgress(t *testing.T) {
+	points := []models.ProgressDataPoint{
+		{MaxWeight: floatPtr(100), E1RM: floatPtr(110), Volume: 3000},
+		{Volume: 0},
+	}
+
+	convertProgress(points, units.Pounds)
+
+	if *points[0].MaxWeight != 220 || *points[0].E1RM != 242.5 || points[0].Volume != 6613.9 {
+		t.Errorf("point = %v, %v, %v", *points[0].MaxWeight, *points[0].E1RM, points[0].Volume)
+	}
+	if points[1].MaxWeight != nil || points[1].E1RM != nil || points[1].Volume != 0 {
+		t.Errorf("empty point = %+v", points[1])
+	}
+}
+
+func TestConvertRecordKeepsEnteredWeight(t *testing.T) {
+	tests := []struct {
+		name       string
+		kg         float64
+		weight     float64
+		weightUnit string
+		unit       string
+		want       float64
+	}{
+		{"fractional lb in lb", 103.19, 227.5, units.Pounds, units.Pounds, 227.5},
+		{"fractional kg in kg", 101.25, 101.25, units.Kilograms, units.Kilograms, 101.25},
+		{"lb in kg", 103.19, 227.5, units.Pounds, units.Kilograms, 103},
+		{"cached kg", 100, 100, "", units.Pounds, 220},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, category := range []string{models.PRCategoryHeaviestSingle, models.PRCategoryRepMax} {
+				record := &models.Record{Category: category, Value: tt.kg, Weight: floatPtr(tt.weight), WeightUnit: tt.weightUnit}
+				convertRecord(record, tt.unit)
+				if record.Value != tt.want || *record.Weight != tt.want || record.WeightUnit != tt.unit {
+					t.Errorf("%s = %v (weight %v %s), want %v %s", category, record.Value, *record.Weight, record.WeightUnit, tt.want, tt.unit)
+				}
+			}
+		})
+	}
+}
+
+func TestConvertPREvents(t *testing.T) {
+	lb := units.Pounds
+	events := []models.PREvent{
+		{
+			Category:      models.PRCategoryHeaviestSingle,
+			OldValue:      102.06,
+			NewValue:      103.19,
+			OldWeight:     floatPtr(225),
+			OldWeightUnit: &lb,
+			NewWeight:     floatPtr(227.5),
+			NewWeightUnit: &lb,
+		},
+		// Events recorded before entered weights were kept fall back to
+		// rounding the kilogram value.
+		{Category: models.PRCategoryRepMax, OldValue: 100, NewValue: 102.5},
+		{Category: models.PRCategoryE1RM, OldValue: 100, NewValue: 110},
+	}
+
+	convertPREvents(events, units.Pounds)
+
+	if events[0].OldValue != 225 || events[0].NewValue != 227.5 {
+		t.Errorf("lb PR = %v -> %v, want 225 -> 227.5", events[0].OldValue, events[0].NewValue)
+	}
+	if events[1].OldValue != 220 || events[1].NewValue != 226 {
+		t.Errorf("kg-only PR = %v -> %v, want 220 -> 226", events[1].OldValue, events[1].NewValue)
+	}
+	if events[2].OldValue != 220.5 || events[2].NewValue != 242.5 {
+		t.Errorf("e1RM PR = %v -> %v, want 220.5 -> 242.5", events[2].OldValue, events[2].NewValue)
+	}
+}
+
+func TestConvertProgressKeepsEnteredWeight(t *testing.T) {
+	points := []models.ProgressDataPoint{
+		{MaxWeight: floatPtr(227.5), MaxWeightUnit: units.Pounds},
+		{MaxWeight: floatPtr(101.25), MaxWeightUnit: units.Kilograms},
+	}
+
+	convertProgress(points, units.Pounds)
+
+	if *points[0].MaxWeight != 227.5 || points[0].MaxWeightUnit != units.Pounds {
+		t.Errorf("lb point = %v %s, want 227.5 lb", *points[0].MaxWeight, points[0].MaxWeightUnit)
+	}
+	if *points[1].MaxWeight != 223 {
+		t.Errorf("kg point = %v, want 223", *points[1].MaxWeight)
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/damion-14/cadence/backend/internal/middleware"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/services"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+type UserHandler struct {
+	userService *services.UserService
+}
+
+func NewUserHandler(userService *services.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+func (h *UserHandler) GetMe(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	user, err := h.userService.GetUser(r.Context(), userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			respondError(w, r, models.ErrNotFound)
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.UserResponse{
+		User: *user,
+	})
+}
+
+func (h *UserHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	var req models.UpdatePreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	if req.PreferredUnit != nil {
+		*req.PreferredUnit = strings.ToLower(strings.TrimSpace(*req.PreferredUnit))
+		if !units.IsValid(*req.PreferredUnit) {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Preferred unit must be kg or lb", 400))
+			return
+		}
+	}
+
+	if req.Timezone != nil {
+		*req.Timezone = strings.TrimSpace(*req.Timezone)
+		if !isValidTimezone(*req.Timezone) {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Timezone must be an IANA zone name, e.g. America/Los_Angeles", 400))
+			return
+		}
+	}
+
+	user, err := h.userService.UpdatePreferences(r.Context(), userID, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			respondError(w, r, models.ErrNotFound)
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, models.UserResponse{
+		User: *user,
+	})
+}
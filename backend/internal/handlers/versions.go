@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/services"
+)
+
+// etag formats a workout or exercise version as an entity tag. Clients send
+// it back in If-Match to make sure they are not overwriting a newer edit.
+func etag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// parseIfMatch returns the version the client expects, or nil when it sent
+// no If-Match or "*".
+func parseIfMatch(r *http.Request) (*int, *models.AppError) {
+	value := strings.TrimSpace(r.Header.Get("If-Match"))
+	if value == "" || value == "*" {
+		return nil, nil
+	}
+
+	version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(value, "W/"), `"`))
+	if err != nil {
+		return nil, models.NewAppError("INVALID_INPUT", "If-Match must be an ETag returned by the API", 400)
+	}
+
+	return &version, nil
+}
+
+// versionConflictError maps a stale If-Match to 412 with the current version
+// and state in the details, converted to unit. It returns nil for any other
+// error.
+func versionConflictError(w http.ResponseWriter, err error, unit string) *models.AppError {
+	var conflict *services.VersionConflictError
+	if !errors.As(err, &conflict) {
+		return nil
+	}
+
+	details := map[string]interface{}{
+		"resource":         conflict.Resource,
+		"id":               conflict.ID,
+		"expected_version": conflict.ExpectedVersion,
+		"current_version":  conflict.CurrentVersion,
+	}
+	if conflict.Workout != nil {
+		convertWorkout(conflict.Workout, unit)
+		details["workout"] = conflict.Workout
+	}
+	if conflict.Exercise != nil {
+		convertExercise(conflict.Exercise, unit)
+		details["exercise"] = conflict.Exercise
+	}
+
+	w.Header().Set("ETag", etag(conflict.CurrentVersion))
+
+	appErr := models.NewAppError("PRECONDITION_FAILED", fmt.Sprintf("The %s has changed since it was read", conflict.Resource), 412)
+	appErr.Details = details
+	return appErr
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/middleware"
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+const (
+	// streamWriteTimeout bounds each write to the stream; the connection
+	// itself may stay open indefinitely.
+	streamWriteTimeout = 10 * time.Second
+	streamKeepAlive    = 15 * time.Second
+	restTimerTick      = time.Second
+)
+
+// Stream pushes the user's unfinished workout as server-sent events. It opens
+// with the current workout and rest timer, then sends them again whenever
+// they change on any device, ticks the rest timer every second and reports
+// completion or abandonment.
+func (h *WorkoutHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	ctx := r.Context()
+	stream := &eventStream{w: w, rc: http.NewResponseController(w)}
+
+	// Lift the server's write timeout for the stream; every write sets its
+	// own deadline instead.
+	if err := stream.rc.SetWriteDeadline(time.Time{}); err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	// Subscribe before reading the current state so no change slips between.
+	events, err := h.workoutService.SubscribeWorkoutEvents(ctx, userID)
+	if err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	workout, err := h.workoutService.GetActiveWorkout(ctx, userID)
+	if err != nil {
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	workoutID := 0
+	if workout != nil {
+		workoutID = workout.ID
+		convertWorkout(workout, unit)
+	}
+	if err := stream.send(models.WorkoutEventUpdated, models.WorkoutStreamData{Workout: workout}); err != nil {
+		return
+	}
+
+	timer := h.streamRestTimer(ctx, userID, workoutID)
+	if err := stream.send(models.WorkoutEventRestTimer, models.RestTimerResponse{Timer: timer}); err != nil {
+		return
+	}
+
+	ticker := time.NewTicker(restTimerTick)
+	defer ticker.Stop()
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if event.Type == models.WorkoutEventRestTimer {
+				timer = h.streamRestTimer(ctx, userID, workoutID)
+				if err := stream.send(event.Type, models.RestTimerResponse{Timer: timer}); err != nil {
+					return
+				}
+				continue
+			}
+
+			workout, err := h.streamWorkout(ctx, userID, event)
+			if err != nil {
+				fmt.Printf("Failed to load workout for stream: %v\n", err)
+				continue
+			}
+
+			workoutID = 0
+			timer = nil
+			if workout != nil && event.Type == models.WorkoutEventUpdated {
+				workoutID = workout.ID
+				timer = h.streamRestTimer(ctx, userID, workoutID)
+			}
+			if workout != nil {
+				convertWorkout(workout, unit)
+			}
+
+			if err := stream.send(event.Type, models.WorkoutStreamData{Workout: workout}); err != nil {
+				return
+			}
+
+		case now := <-ticker.C:
+			if timer == nil {
+				continue
+			}
+
+			timer.SetRemaining(now)
+			if err := stream.send(models.WorkoutEventRestTimerTick, models.RestTimerResponse{Timer: timer}); err != nil {
+				return
+			}
+			if timer.RemainingSeconds == 0 {
+				timer = nil
+			}
+
+		case <-keepAlive.C:
+			if err := stream.write(": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// streamWorkout loads the workout an event refers to: the finished workout
+// for completions and abandonments, otherwise whatever is unfinished now.
+func (h *WorkoutHandler) streamWorkout(ctx context.Context, userID int, event models.WorkoutEvent) (*models.WorkoutSession, error) {
+	if event.Type == models.WorkoutEventUpdated || event.WorkoutSessionID == 0 {
+		return h.workoutService.GetActiveWorkout(ctx, userID)
+	}
+
+	workout, err := h.workoutService.GetWorkout(ctx, event.WorkoutSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workout.UserID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	return workout, nil
+}
+
+func (h *WorkoutHandler) streamRestTimer(ctx context.Context, userID, workoutID int) *models.RestTimer {
+	if workoutID == 0 {
+		return nil
+	}
+
+	timer, err := h.workoutService.GetRestTimer(ctx, userID, workoutID)
+	if err != nil {
+		fmt.Printf("Failed to load rest timer for stream: %v\n", err)
+		return nil
+	}
+
+	return timer
+}
+
+type eventStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+func (s *eventStream) send(event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return s.write(fmt.Sprintf("event: %s\ndata: %s\n\n", event, payload))
+}
+
+func (s *eventStream) write(message string) error {
+	if err := s.rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout)); err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(s.w, message); err != nil {
+		return err
+	}
+
+	return s.rc.Flush()
+}
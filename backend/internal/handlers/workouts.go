@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,11 +15,13 @@ import (
 
 type WorkoutHandler struct {
 	workoutService *services.WorkoutService
+	userService    *services.UserService
 }
 
-func NewWorkoutHandler(workoutService *services.WorkoutService) *WorkoutHandler {
+func NewWorkoutHandler(workoutService *services.WorkoutService, userService *services.UserService) *WorkoutHandler {
 	return &WorkoutHandler{
 		workoutService: workoutService,
+		userService:    userService,
 	}
 }
 
@@ -34,17 +38,276 @@ func (h *WorkoutHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	workout, err := h.workoutService.CreateWorkout(r.Context(), userID, req.Name)
+	req.Name = strings.TrimSpace(req.Name)
+
+	replace := r.URL.Query().Get("replace")
+	if replace != "" && replace != models.ReplaceComplete && replace != models.ReplaceAbandon {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "replace must be complete or abandon", 400))
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	workout, events, err := h.workoutService.CreateWorkout(r.Context(), userID, req.Name, req.RoutineID, replace)
 	if err != nil {
+		var conflict *services.WorkoutConflictError
+		if errors.As(err, &conflict) {
+			respondError(w, r, workoutConflict(conflict, "An unfinished workout already exists; pass replace=complete or replace=abandon to end it"))
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			respondError(w, r, models.NewAppError("NOT_FOUND", "Routine not found", 404))
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			respondError(w, r, models.ErrForbidden)
+			return
+		}
 		respondError(w, r, models.ErrInternalServer)
 		return
 	}
 
+	convertWorkout(workout, unit)
+	convertPREvents(events, unit)
+	respondJSON(w, http.StatusCreated, models.CreateWorkoutResponse{
+		Workout:  *workout,
+		PREvents: events,
+	})
+}
+
+// Log records a workout that already happened, with explicit start and end
+// times and all of its exercises and sets.
+func (h *WorkoutHandler) Log(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	var req models.LogWorkoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+
+	if appErr := validateNotes(req.Notes); appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	if req.StartedAt == nil || req.CompletedAt == nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "started_at and completed_at are required", 400))
+		return
+	}
+
+	if len(req.Exercises) == 0 {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "At least one exercise is required", 400))
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	for i := range req.Exercises {
+		exercise := &req.Exercises[i]
+
+		exercise.Name = strings.TrimSpace(exercise.Name)
+		if exercise.Name == "" && exercise.ExerciseDefinitionID == nil {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Exercise name or exercise_definition_id is required", 400))
+			return
+		}
+
+		if appErr := validateNotes(exercise.Notes); appErr != nil {
+			respondError(w, r, appErr)
+			return
+		}
+
+		if len(exercise.Sets) == 0 {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "At least one set is required for each exercise", 400))
+			return
+		}
+
+		for j := range exercise.Sets {
+			if appErr := validateSetInput(&exercise.Sets[j], unit); appErr != nil {
+				respondError(w, r, appErr)
+				return
+			}
+		}
+	}
+
+	workout, err := h.workoutService.LogWorkout(r.Context(), userID, req)
+	if err != nil {
+		if appErr := workoutTimesError(err); appErr != nil {
+			respondError(w, r, appErr)
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			respondError(w, r, models.NewAppError("NOT_FOUND", "Exercise definition not found", 404))
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	convertWorkout(workout, unit)
 	respondJSON(w, http.StatusCreated, models.CreateWorkoutResponse{
 		Workout: *workout,
 	})
 }
 
+// Update renames a workout or moves its times. Completed workouts stay
+// editable; each change to one is kept as a revision.
+func (h *WorkoutHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid workout ID", 400))
+		return
+	}
+
+	ifMatch, appErr := parseIfMatch(r)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	var req models.UpdateWorkoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid request body", 400))
+		return
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		req.Name = &name
+	}
+
+	if appErr := validateNotes(req.Notes); appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	workout, err := h.workoutService.UpdateWorkout(r.Context(), userID, workoutID, req, ifMatch)
+	if err != nil {
+		if appErr := versionConflictError(w, err, unit); appErr != nil {
+			respondError(w, r, appErr)
+			return
+		}
+		if appErr := workoutTimesError(err); appErr != nil {
+			respondError(w, r, appErr)
+			return
+		}
+		if strings.Contains(err.Error(), "not found") {
+			respondError(w, r, models.ErrNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			respondError(w, r, models.ErrForbidden)
+			return
+		}
+		if strings.Contains(err.Error(), "cannot be edited") {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", "Workout cannot be edited", 400))
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	convertWorkout(workout, unit)
+	w.Header().Set("ETag", etag(workout.Version))
+	respondJSON(w, http.StatusOK, models.GetWorkoutResponse{
+		Workout: *workout,
+	})
+}
+
+// Revisions lists the edits made to a workout after it was completed.
+func (h *WorkoutHandler) Revisions(w http.ResponseWriter, r *http.Request) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid workout ID", 400))
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	revisions, err := h.workoutService.GetRevisions(r.Context(), userID, workoutID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			respondError(w, r, models.ErrNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			respondError(w, r, models.ErrForbidden)
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	for i := range revisions {
+		convertWorkout(&revisions[i].Before, unit)
+		convertWorkout(&revisions[i].After, unit)
+	}
+
+	respondJSON(w, http.StatusOK, models.WorkoutRevisionsResponse{
+		Revisions: revisions,
+		Unit:      unit,
+	})
+}
+
+// workoutTimesError maps rejected start and end times, and overlaps with
+// other workouts, to client errors. It returns nil for any other error.
+func workoutTimesError(err error) *models.AppError {
+	var conflict *services.WorkoutConflictError
+	if errors.As(err, &conflict) {
+		return workoutConflict(conflict, "Workout overlaps an existing workout")
+	}
+
+	if strings.Contains(err.Error(), "invalid workout times") {
+		return models.NewAppError("INVALID_INPUT", err.Error(), 400)
+	}
+
+	return nil
+}
+
+// workoutConflict reports a clash with another workout, naming it in the
+// details so clients can open or end it.
+func workoutConflict(conflict *services.WorkoutConflictError, message string) *models.AppError {
+	appErr := models.NewAppError("CONFLICT", message, 409)
+	appErr.Details = map[string]int{"workout_id": conflict.WorkoutID}
+	return appErr
+}
+
 func (h *WorkoutHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	userID := middleware.GetUserID(r.Context())
 	if userID == 0 {
@@ -73,6 +336,14 @@ func (h *WorkoutHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	convertWorkout(workout, unit)
+	w.Header().Set("ETag", etag(workout.Version))
 	respondJSON(w, http.StatusOK, models.GetWorkoutResponse{
 		Workout: *workout,
 	})
@@ -85,6 +356,12 @@ func (h *WorkoutHandler) GetActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
 	workout, err := h.workoutService.GetActiveWorkout(r.Context(), userID)
 	if err != nil {
 		respondError(w, r, models.ErrInternalServer)
@@ -98,6 +375,7 @@ func (h *WorkoutHandler) GetActive(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	convertWorkout(workout, unit)
 	respondJSON(w, http.StatusOK, models.GetWorkoutResponse{
 		Workout: *workout,
 	})
@@ -116,9 +394,19 @@ func (h *WorkoutHandler) Complete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	workout, err := h.workoutService.CompleteWorkout(r.Context(), userID, workoutID)
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	workout, events, err := h.workoutService.CompleteWorkout(r.Context(), userID, workoutID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "already completed") {
+		if strings.Contains(err.Error(), "unauthorized") {
+			respondError(w, r, models.ErrForbidden)
+			return
+		}
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "already completed") || strings.Contains(err.Error(), "invalid transition") {
 			respondError(w, r, models.NewAppError("INVALID_INPUT", err.Error(), 400))
 			return
 		}
@@ -126,6 +414,66 @@ func (h *WorkoutHandler) Complete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	convertWorkout(workout, unit)
+	convertPREvents(events, unit)
+	respondJSON(w, http.StatusOK, models.CompleteWorkoutResponse{
+		Workout:  *workout,
+		PREvents: events,
+		Unit:     unit,
+	})
+}
+
+func (h *WorkoutHandler) Pause(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.workoutService.PauseWorkout)
+}
+
+func (h *WorkoutHandler) Resume(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.workoutService.ResumeWorkout)
+}
+
+func (h *WorkoutHandler) Abandon(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, h.workoutService.AbandonWorkout)
+}
+
+// transition serves the status changes that need nothing but the workout ID.
+func (h *WorkoutHandler) transition(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, userID, workoutID int) (*models.WorkoutSession, error)) {
+	userID := middleware.GetUserID(r.Context())
+	if userID == 0 {
+		respondError(w, r, models.ErrUnauthorized)
+		return
+	}
+
+	workoutID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		respondError(w, r, models.NewAppError("INVALID_INPUT", "Invalid workout ID", 400))
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	workout, err := apply(r.Context(), userID, workoutID)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			respondError(w, r, models.ErrNotFound)
+			return
+		}
+		if strings.Contains(err.Error(), "unauthorized") {
+			respondError(w, r, models.ErrForbidden)
+			return
+		}
+		if strings.Contains(err.Error(), "invalid transition") {
+			respondError(w, r, models.NewAppError("INVALID_INPUT", err.Error(), 400))
+			return
+		}
+		respondError(w, r, models.ErrInternalServer)
+		return
+	}
+
+	convertWorkout(workout, unit)
 	respondJSON(w, http.StatusOK, models.GetWorkoutResponse{
 		Workout: *workout,
 	})
@@ -144,7 +492,23 @@ func (h *WorkoutHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.workoutService.DeleteWorkout(r.Context(), userID, workoutID); err != nil {
+	ifMatch, appErr := parseIfMatch(r)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	unit, appErr := resolveUnit(r, h.userService, userID)
+	if appErr != nil {
+		respondError(w, r, appErr)
+		return
+	}
+
+	if err := h.workoutService.DeleteWorkout(r.Context(), userID, workoutID, ifMatch); err != nil {
+		if appErr := versionConflictError(w, err, unit); appErr != nil {
+			respondError(w, r, appErr)
+			return
+		}
 		if strings.Contains(err.Error(), "not found") {
 			respondError(w, r, models.ErrNotFound)
 			return
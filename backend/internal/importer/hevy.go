@@ -0,0 +1,109 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+// hevyTimeLayouts are the start_time/end_time formats seen in Hevy exports,
+// all local wall-clock times.
+var hevyTimeLayouts = []string{
+	"2 Jan 2006, 15:04",
+	"2 Jan 2006 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// hevyParser reads Hevy's workout CSV export. Weights are in whichever unit
+// the column is named after.
+type hevyParser struct {
+	columns      columnIndex
+	opts         Options
+	weightColumn string
+	unit         string
+}
+
+func newHevyParser(columns columnIndex, opts Options) *hevyParser {
+	p := &hevyParser{columns: columns, opts: opts, weightColumn: "weight_lbs", unit: units.Pounds}
+	if columns.has("weight_kg") {
+		p.weightColumn = "weight_kg"
+		p.unit = units.Kilograms
+	}
+	return p
+}
+
+func (p *hevyParser) source() string {
+	return SourceHevy
+}
+
+func (p *hevyParser) parse(record []string) (*row, error) {
+	get := func(name string) string { return p.columns.get(record, name) }
+
+	reps, err := strconv.Atoi(get("reps"))
+	if err != nil || reps <= 0 {
+		return nil, nil
+	}
+
+	startedAt, err := p.parseTime(get("start_time"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid start_time %q", get("start_time"))
+	}
+
+	completedAt, err := p.parseTime(get("end_time"))
+	if err != nil {
+		completedAt = startedAt
+	}
+
+	exercise := get("exercise_title")
+	if exercise == "" {
+		return nil, fmt.Errorf("exercise_title is missing")
+	}
+
+	weight, err := parseOptionalFloat(get(p.weightColumn))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", p.weightColumn, get(p.weightColumn))
+	}
+
+	rpe, err := parseOptionalFloat(get("rpe"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid rpe %q", get("rpe"))
+	}
+
+	name := get("title")
+	if name == "" {
+		name = "Imported Workout"
+	}
+
+	return &row{
+		workoutName: name,
+		startedAt:   startedAt,
+		completedAt: completedAt,
+		exercise:    exercise,
+		set:         newSetInput(reps, weight, p.unit, hevySetType(get("set_type")), rpe),
+	}, nil
+}
+
+func (p *hevyParser) parseTime(value string) (time.Time, error) {
+	for _, layout := range hevyTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, p.opts.Location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", value)
+}
+
+func hevySetType(value string) string {
+	switch value {
+	case "warmup":
+		return models.SetTypeWarmup
+	case "dropset":
+		return models.SetTypeDrop
+	case "failure":
+		return models.SetTypeFailure
+	}
+	return models.SetTypeNormal
+}
@@ -0,0 +1,255 @@
+// Package importer parses workout history exported from other tracking
+// apps. It only understands files; mapping exercise names onto the catalog
+// and writing workouts is left to the caller.
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+const (
+	SourceStrong = "strong"
+	SourceHevy   = "hevy"
+)
+
+// maxRowErrors caps how many bad rows are reported back individually.
+const maxRowErrors = 50
+
+// Options supplies what the file itself does not say. Strong exports carry
+// neither a weight unit nor a timezone, so both come from the user.
+type Options struct {
+	Unit     string
+	Location *time.Location
+}
+
+// Workout is one parsed session. Exercises keep the order they first appear
+// in within the workout.
+type Workout struct {
+	Name        string
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Exercises   []Exercise
+}
+
+type Exercise struct {
+	Name string
+	Sets []models.SetInput
+}
+
+type Result struct {
+	Source      string
+	Workouts    []Workout
+	RowsSkipped int
+	Errors      []models.ImportRowError
+}
+
+// Parse detects whether r holds a Strong or Hevy CSV export and parses it.
+// Rows that cannot be used, such as cardio entries without reps, are counted
+// in RowsSkipped; malformed rows are also listed in Errors.
+func Parse(r io.Reader, opts Options) (*Result, error) {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	buffered := bufio.NewReader(r)
+	firstLine, err := buffered.Peek(4096)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+	if i := bytes.IndexByte(firstLine, '\n'); i >= 0 {
+		firstLine = firstLine[:i]
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	// Newer Strong versions separate fields with semicolons.
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("import file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	columns := indexColumns(header)
+
+	var p rowParser
+	switch {
+	case columns.has("exercise_title") && columns.has("start_time"):
+		p = newHevyParser(columns, opts)
+	case columns.has("exercise name") && columns.has("date"):
+		p = newStrongParser(columns, opts)
+	default:
+		return nil, fmt.Errorf("unrecognized CSV format: expected a Strong or Hevy export")
+	}
+
+	result := &Result{Source: p.source(), Workouts: []Workout{}, Errors: []models.ImportRowError{}}
+	builder := newWorkoutBuilder()
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			line := 0
+			if parseErr, ok := err.(*csv.ParseError); ok {
+				line = parseErr.StartLine
+			}
+			result.addError(line, err.Error())
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		row, err := p.parse(record)
+		if err != nil {
+			result.addError(line, err.Error())
+			continue
+		}
+		if row == nil {
+			result.RowsSkipped++
+			continue
+		}
+
+		builder.add(row)
+	}
+
+	result.Workouts = builder.workouts
+	return result, nil
+}
+
+func (r *Result) addError(line int, message string) {
+	r.RowsSkipped++
+	if len(r.Errors) < maxRowErrors {
+		r.Errors = append(r.Errors, models.ImportRowError{Line: line, Message: message})
+	}
+}
+
+// row is one set in a foreign export, with the workout it belongs to.
+type row struct {
+	workoutName string
+	startedAt   time.Time
+	completedAt time.Time
+	exercise    string
+	set         models.SetInput
+}
+
+type rowParser interface {
+	source() string
+	// parse returns nil without an error for rows that carry no liftable set.
+	parse(record []string) (*row, error)
+}
+
+// workoutBuilder groups consecutive rows into workouts. Both apps write a
+// workout's rows together, keyed by its start time and name.
+type workoutBuilder struct {
+	workouts []Workout
+}
+
+func newWorkoutBuilder() *workoutBuilder {
+	return &workoutBuilder{workouts: []Workout{}}
+}
+
+func (b *workoutBuilder) add(r *row) {
+	last := len(b.workouts) - 1
+	if last < 0 || !b.workouts[last].StartedAt.Equal(r.startedAt) || b.workouts[last].Name != r.workoutName {
+		b.workouts = append(b.workouts, Workout{
+			Name:        r.workoutName,
+			StartedAt:   r.startedAt,
+			CompletedAt: r.completedAt,
+			Exercises:   []Exercise{},
+		})
+		last++
+	}
+	workout := &b.workouts[last]
+
+	index := -1
+	for i := range workout.Exercises {
+		if workout.Exercises[i].Name == r.exercise {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		workout.Exercises = append(workout.Exercises, Exercise{Name: r.exercise, Sets: []models.SetInput{}})
+		index = len(workout.Exercises) - 1
+	}
+
+	workout.Exercises[index].Sets = append(workout.Exercises[index].Sets, r.set)
+}
+
+type columnIndex map[string]int
+
+func indexColumns(header []string) columnIndex {
+	columns := columnIndex{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	return columns
+}
+
+func (c columnIndex) has(name string) bool {
+	_, ok := c[name]
+	return ok
+}
+
+func (c columnIndex) get(record []string, name string) string {
+	i, ok := c[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func parseOptionalFloat(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// newSetInput builds a completed set, treating a missing or zero weight as
+// bodyweight. Out of range RPE values are dropped rather than rejected.
+func newSetInput(reps int, weight *float64, unit, setType string, rpe *float64) models.SetInput {
+	completed := true
+	set := models.SetInput{
+		Reps:        reps,
+		WeightUnit:  unit,
+		IsCompleted: &completed,
+		SetType:     setType,
+	}
+
+	if weight == nil || *weight <= 0 {
+		set.IsBodyweight = true
+	} else {
+		set.Weight = weight
+	}
+
+	if rpe != nil && *rpe >= 1 && *rpe <= 10 {
+		rounded := math.Round(*rpe*2) / 2
+		set.RPE = &rounded
+	}
+
+	return set
+}
@@ -0,0 +1,289 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/export"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+func TestParseStrong(t *testing.T) {
+	csv := strings.Join([]string{
+		"\ufeffDate,Workout Name,Duration,Exercise Name,Set Order,Weight,Reps,Distance,Seconds,Notes,Workout Notes,RPE",
+		"2024-03-04 18:30:00,Push,1h 5m,Bench Press,W,40,10,,,,,",
+		"2024-03-04 18:30:00,Push,1h 5m,Bench Press,1,80,5,,,,,8.3",
+		"2024-03-04 18:30:00,Push,1h 5m,Dips,1,,12,,,,,",
+		"2024-03-04 18:30:00,Push,1h 5m,Bench Press,2,80,5,,,,,",
+		"2024-03-04 18:30:00,Push,1h 5m,Rest Timer,Rest Timer,,0,,90,,,",
+		"2024-03-06 07:00:00,,45m,Squat,1,100,5,,,,,",
+		"not a date,Push,,Bench Press,1,80,5,,,,,",
+	}, "\n")
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Parse(strings.NewReader(csv), Options{Unit: units.Pounds, Location: loc})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if result.Source != SourceStrong {
+		t.Errorf("Source = %q, want %q", result.Source, SourceStrong)
+	}
+	if result.RowsSkipped != 2 {
+		t.Errorf("RowsSkipped = %d, want 2", result.RowsSkipped)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Line != 8 {
+		t.Errorf("Errors = %+v, want one error on line 8", result.Errors)
+	}
+	if len(result.Workouts) != 2 {
+		t.Fatalf("got %d workouts, want 2", len(result.Workouts))
+	}
+
+	push := result.Workouts[0]
+	wantStart := time.Date(2024, 3, 4, 18, 30, 0, 0, loc)
+	if push.Name != "Push" || !push.StartedAt.Equal(wantStart) {
+		t.Errorf("workout = %q at %v, want Push at %v", push.Name, push.StartedAt, wantStart)
+	}
+	if want := wantStart.Add(65 * time.Minute); !push.CompletedAt.Equal(want) {
+		t.Errorf("CompletedAt = %v, want %v", push.CompletedAt, want)
+	}
+	if len(push.Exercises) != 2 || push.Exercises[0].Name != "Bench Press" || push.Exercises[1].Name != "Dips" {
+		t.Fatalf("exercises = %+v, want Bench Press then Dips", push.Exercises)
+	}
+
+	bench := push.Exercises[0].Sets
+	if len(bench) != 3 {
+		t.Fatalf("got %d bench sets, want 3", len(bench))
+	}
+	if bench[0].SetType != models.SetTypeWarmup || bench[1].SetType != models.SetTypeNormal {
+		t.Errorf("set types = %q, %q, want warmup then normal", bench[0].SetType, bench[1].SetType)
+	}
+	if bench[1].Weight == nil || *bench[1].Weight != 80 || bench[1].WeightUnit != units.Pounds {
+		t.Errorf("set = %+v, want 80 lb", bench[1])
+	}
+	if bench[1].RPE == nil || *bench[1].RPE != 8.5 {
+		t.Errorf("RPE = %v, want 8.5", bench[1].RPE)
+	}
+
+	dips := push.Exercises[1].Sets
+	if len(dips) != 1 || !dips[0].IsBodyweight || dips[0].Weight != nil {
+		t.Errorf("dips = %+v, want one bodyweight set", dips)
+	}
+
+	if result.Workouts[1].Name != "Imported Workout" {
+		t.Errorf("unnamed workout = %q, want Imported Workout", result.Workouts[1].Name)
+	}
+}
+
+func TestParseStrongSemicolons(t *testing.T) {
+	csv := "Date;Workout Name;Duration;Exercise Name;Set Order;Weight;Weight Unit;Reps\n" +
+		"2024-03-04 18:30:00;Legs;30m;Squat;1;100;kg;5\n"
+
+	result, err := Parse(strings.NewReader(csv), Options{Unit: units.Pounds})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if len(result.Workouts) != 1 || len(result.Workouts[0].Exercises) != 1 {
+		t.Fatalf("workouts = %+v, want one workout with one exercise", result.Workouts)
+	}
+	set := result.Workouts[0].Exercises[0].Sets[0]
+	if set.WeightUnit != units.Kilograms || set.Reps != 5 {
+		t.Errorf("set = %+v, want 5 reps in kg", set)
+	}
+	if !result.Workouts[0].StartedAt.Equal(time.Date(2024, 3, 4, 18, 30, 0, 0, time.UTC)) {
+		t.Errorf("StartedAt = %v, want UTC when no location is given", result.Workouts[0].StartedAt)
+	}
+}
+
+func TestParseHevy(t *testing.T) {
+	csv := strings.Join([]string{
+		"title,start_time,end_time,description,exercise_title,superset_id,exercise_notes,set_index,set_type,weight_kg,reps,distance_km,duration_seconds,rpe",
+		`Pull,"5 Mar 2024, 07:15","5 Mar 2024, 08:00",,Deadlift,,,0,warmup,60,5,,,`,
+		`Pull,"5 Mar 2024, 07:15","5 Mar 2024, 08:00",,Deadlift,,,1,normal,140,3,,,9`,
+		`Pull,"5 Mar 2024, 07:15","5 Mar 2024, 08:00",,Rowing Machine,,,0,normal,,,2,600,`,
+		`Pull,"5 Mar 2024, 07:15",,,Pull Up,,,0,failure,,8,,,`,
+	}, "\n")
+
+	result, err := Parse(strings.NewReader(csv), Options{Unit: units.Pounds})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if result.Source != SourceHevy {
+		t.Errorf("Source = %q, want %q", result.Source, SourceHevy)
+	}
+	if result.RowsSkipped != 1 {
+		t.Errorf("RowsSkipped = %d, want 1", result.RowsSkipped)
+	}
+	if len(result.Workouts) != 1 {
+		t.Fatalf("got %d workouts, want 1", len(result.Workouts))
+	}
+
+	workout := result.Workouts[0]
+	if want := time.Date(2024, 3, 5, 8, 0, 0, 0, time.UTC); !workout.CompletedAt.Equal(want) {
+		t.Errorf("CompletedAt = %v, want %v", workout.CompletedAt, want)
+	}
+	if len(workout.Exercises) != 2 {
+		t.Fatalf("exercises = %+v, want Deadlift and Pull Up", workout.Exercises)
+	}
+
+	deadlift := workout.Exercises[0].Sets
+	if len(deadlift) != 2 || deadlift[0].SetType != models.SetTypeWarmup || deadlift[1].WeightUnit != units.Kilograms {
+		t.Errorf("deadlift = %+v, want a warm-up and a working set in kg", deadlift)
+	}
+
+	pullUp := workout.Exercises[1].Sets
+	if len(pullUp) != 1 || pullUp[0].SetType != models.SetTypeFailure || !pullUp[0].IsBodyweight {
+		t.Errorf("pull up = %+v, want one bodyweight failure set", pullUp)
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"empty", ""},
+		{"unknown header", "a,b,c\n1,2,3\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.csv), Options{}); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestStrongSetType(t *testing.T) {
+	tests := []struct {
+		order  string
+		want   string
+		wantOK bool
+	}{
+		{"1", models.SetTypeNormal, true},
+		{"12", models.SetTypeNormal, true},
+		{"W", models.SetTypeWarmup, true},
+		{"w", models.SetTypeWarmup, true},
+		{"D", models.SetTypeDrop, true},
+		{"F", models.SetTypeFailure, true},
+		{"Rest Timer", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := strongSetType(tt.order)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("strongSetType(%q) = %q, %v, want %q, %v", tt.order, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseStrongDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"1h 5m", time.Hour + 5*time.Minute},
+		{"45m", 45 * time.Minute},
+		{"30s", 30 * time.Second},
+		{"", 0},
+		{"1h oops", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseStrongDuration(tt.value); got != tt.want {
+			t.Errorf("parseStrongDuration(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestStrongExportRoundTrip(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	startedAt := time.Date(2024, 3, 4, 18, 30, 0, 0, loc)
+	completedAt := startedAt.Add(65 * time.Minute)
+	weight := func(v float64) *float64 { return &v }
+	rpe := 8.5
+
+	workout := &models.WorkoutSession{
+		Name:        "Push",
+		StartedAt:   startedAt,
+		CompletedAt: &completedAt,
+		Exercises: []models.Exercise{
+			{Name: "Bench Press", Sets: []models.Set{
+				{SetNumber: 1, SetType: models.SetTypeWarmup, Reps: 10, Weight: weight(95), WeightUnit: units.Pounds, IsCompleted: true},
+				{SetNumber: 2, SetType: models.SetTypeNormal, Reps: 5, Weight: weight(227.5), WeightUnit: units.Pounds, IsCompleted: true, RPE: &rpe},
+				{SetNumber: 3, SetType: models.SetTypeDrop, Reps: 8, Weight: weight(185), WeightUnit: units.Pounds, IsCompleted: true},
+				{SetNumber: 4, SetType: models.SetTypeFailure, Reps: 6, Weight: weight(185), WeightUnit: units.Pounds, IsCompleted: true},
+				{SetNumber: 5, SetType: models.SetTypeNormal, Reps: 5, Weight: weight(225), WeightUnit: units.Pounds},
+			}},
+			{Name: "Dips", Sets: []models.Set{
+				{SetNumber: 1, SetType: models.SetTypeNormal, Reps: 12, WeightUnit: units.Pounds, IsBodyweight: true, IsCompleted: true},
+			}},
+		},
+	}
+
+	var buf bytes.Buffer
+	w, err := export.NewWriter(&buf, export.FormatStrong, export.Options{Unit: units.Pounds, Location: loc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteWorkout(workout); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Parse(&buf, Options{Unit: units.Pounds, Location: loc})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(result.Errors) != 0 || len(result.Workouts) != 1 {
+		t.Fatalf("result = %+v, want one workout without errors", result)
+	}
+
+	imported := result.Workouts[0]
+	if imported.Name != workout.Name || !imported.StartedAt.Equal(startedAt) || !imported.CompletedAt.Equal(completedAt) {
+		t.Errorf("workout = %q %v-%v, want %q %v-%v", imported.Name, imported.StartedAt, imported.CompletedAt, workout.Name, startedAt, completedAt)
+	}
+	if len(imported.Exercises) != 2 {
+		t.Fatalf("exercises = %+v, want Bench Press and Dips", imported.Exercises)
+	}
+
+	// The unperformed set is not exported.
+	want := workout.Exercises[0].Sets[:4]
+	got := imported.Exercises[0].Sets
+	if len(got) != len(want) {
+		t.Fatalf("got %d bench sets, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].SetType != want[i].SetType || got[i].Reps != want[i].Reps || got[i].Weight == nil ||
+			*got[i].Weight != *want[i].Weight || got[i].WeightUnit != want[i].WeightUnit {
+			t.Errorf("set %d = %s %dx%v %s, want %s %dx%v %s", i+1,
+				got[i].SetType, got[i].Reps, got[i].Weight, got[i].WeightUnit,
+				want[i].SetType, want[i].Reps, *want[i].Weight, want[i].WeightUnit)
+		}
+	}
+	if got[1].RPE == nil || *got[1].RPE != rpe {
+		t.Errorf("RPE = %v, want %v", got[1].RPE, rpe)
+	}
+
+	dips := imported.Exercises[1].Sets
+	if len(dips) != 1 || !dips[0].IsBodyweight || dips[0].Weight != nil {
+		t.Errorf("dips = %+v, want one bodyweight set", dips)
+	}
+}
@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/export"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+// strongParser reads the Strong app's CSV export, the same layout Cadence
+// writes for format=strong.
+type strongParser struct {
+	columns columnIndex
+	opts    Options
+}
+
+func newStrongParser(columns columnIndex, opts Options) *strongParser {
+	return &strongParser{columns: columns, opts: opts}
+}
+
+func (p *strongParser) source() string {
+	return SourceStrong
+}
+
+func (p *strongParser) parse(record []string) (*row, error) {
+	get := func(name string) string { return p.columns.get(record, name) }
+
+	setType, ok := strongSetType(get("set order"))
+	if !ok {
+		return nil, nil
+	}
+
+	reps, err := strconv.Atoi(get("reps"))
+	if err != nil || reps <= 0 {
+		return nil, nil
+	}
+
+	startedAt, err := time.ParseInLocation(export.StrongDateLayout, get("date"), p.opts.Location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", get("date"))
+	}
+
+	exercise := get("exercise name")
+	if exercise == "" {
+		return nil, fmt.Errorf("exercise name is missing")
+	}
+
+	weight, err := parseOptionalFloat(get("weight"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid weight %q", get("weight"))
+	}
+
+	rpe, err := parseOptionalFloat(get("rpe"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RPE %q", get("rpe"))
+	}
+
+	unit := p.opts.Unit
+	if u := strings.ToLower(get("weight unit")); units.IsValid(u) {
+		unit = u
+	} else if u == "lbs" {
+		unit = units.Pounds
+	}
+
+	name := get("workout name")
+	if name == "" {
+		name = "Imported Workout"
+	}
+
+	return &row{
+		workoutName: name,
+		startedAt:   startedAt,
+		completedAt: startedAt.Add(parseStrongDuration(get("duration"))),
+		exercise:    exercise,
+		set:         newSetInput(reps, weight, unit, setType, rpe),
+	}, nil
+}
+
+// strongSetType reads the Set Order column: a set number, or a letter for
+// warm-up, drop and failure sets in newer exports. Anything else, such as
+// rest timer rows, is not a set.
+func strongSetType(order string) (string, bool) {
+	switch strings.ToUpper(order) {
+	case "W":
+		return models.SetTypeWarmup, true
+	case "D":
+		return models.SetTypeDrop, true
+	case "F":
+		return models.SetTypeFailure, true
+	}
+
+	if _, err := strconv.Atoi(order); err == nil {
+		return models.SetTypeNormal, true
+	}
+	return "", false
+}
+
+// parseStrongDuration reads durations such as "1h 5m", "45m" or "30s".
+// Unreadable values count as zero.
+func parseStrongDuration(value string) time.Duration {
+	var total time.Duration
+	for _, part := range strings.Fields(value) {
+		d, err := time.ParseDuration(part)
+		if err != nil {
+			return 0
+		}
+		total += d
+	}
+	return total
+}
@@ -2,7 +2,10 @@ package middleware
 
 import "context"
 
-const UserIDKey contextKey = "user_id"
+const (
+	UserIDKey  contextKey = "user_id"
+	TokenIDKey contextKey = "token_id"
+)
 
 func GetUserID(ctx context.Context) int {
 	if userID, ok := ctx.Value(UserIDKey).(int); ok {
@@ -14,3 +17,14 @@ func GetUserID(ctx context.Context) int {
 func SetUserID(ctx context.Context, userID int) context.Context {
 	return context.WithValue(ctx, UserIDKey, userID)
 }
+
+func GetTokenID(ctx context.Context) string {
+	if tokenID, ok := ctx.Value(TokenIDKey).(string); ok {
+		return tokenID
+	}
+	return ""
+}
+
+func SetTokenID(ctx context.Context, tokenID string) context.Context {
+	return context.WithValue(ctx, TokenIDKey, tokenID)
+}
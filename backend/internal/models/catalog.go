@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+type ExerciseDefinition struct {
+	ID             int      `json:"id"`
+	UserID         *int     `json:"user_id,omitempty"`
+	Name           string   `json:"name"`
+	Aliases        []string `json:"aliases"`
+	PrimaryMuscles []string `json:"primary_muscles"`
+	Equipment      string   `json:"equipment"`
+	IsBodyweight   bool     `json:"is_bodyweight"`
+	UnitType       string   `json:"unit_type"`
+	// DefaultRestSeconds is the rest timer length after a set; nil means
+	// the application default.
+	DefaultRestSeconds *int      `json:"default_rest_seconds,omitempty"`
+	IsCustom           bool      `json:"is_custom"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type CreateExerciseDefinitionRequest struct {
+	Name               string   `json:"name"`
+	Aliases            []string `json:"aliases"`
+	PrimaryMuscles     []string `json:"primary_muscles"`
+	Equipment          string   `json:"equipment"`
+	IsBodyweight       bool     `json:"is_bodyweight"`
+	UnitType           string   `json:"unit_type"`
+	DefaultRestSeconds *int     `json:"default_rest_seconds,omitempty"`
+}
+
+type ExerciseDefinitionResponse struct {
+	Definition ExerciseDefinition `json:"definition"`
+}
+
+type CatalogResponse struct {
+	Definitions []ExerciseDefinition `json:"definitions"`
+}
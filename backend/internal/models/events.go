@@ -0,0 +1,26 @@
+package models
+
+// Workout event types. They are published whenever the user's unfinished
+// workout or rest timer changes and double as the SSE event names on the
+// active workout stream, which adds WorkoutEventRestTimerTick once a second
+// while a timer runs.
+const (
+	WorkoutEventUpdated       = "workout.updated"
+	WorkoutEventCompleted     = "workout.completed"
+	WorkoutEventAbandoned     = "workout.abandoned"
+	WorkoutEventRestTimer     = "rest_timer.updated"
+	WorkoutEventRestTimerTick = "rest_timer.tick"
+)
+
+// WorkoutEvent is the message published to other API instances. It only
+// names what changed; subscribers read the current state themselves.
+type WorkoutEvent struct {
+	Type             string `json:"type"`
+	WorkoutSessionID int    `json:"workout_session_id"`
+}
+
+// WorkoutStreamData is the payload of workout events on the stream. Workout
+// is null when the user has no unfinished workout.
+type WorkoutStreamData struct {
+	Workout *WorkoutSession `json:"workout"`
+}
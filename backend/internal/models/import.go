@@ -0,0 +1,46 @@
+package models
+
+const (
+	// MappingMatched means the name or an alias matched a catalog definition.
+	MappingMatched = "matched"
+	// MappingOverride means the client chose the definition in the request.
+	MappingOverride = "override"
+	// MappingNew means no definition matched; a custom one is created.
+	MappingNew = "new"
+)
+
+// ExerciseMapping reports which catalog definition a foreign exercise name
+// maps onto. Suggestions are the closest catalog names for unmatched ones,
+// for the client to offer as overrides.
+type ExerciseMapping struct {
+	SourceName           string               `json:"source_name"`
+	ExerciseDefinitionID *int                 `json:"exercise_definition_id,omitempty"`
+	ExerciseName         string               `json:"exercise_name"`
+	Match                string               `json:"match"`
+	SetCount             int                  `json:"set_count"`
+	Suggestions          []ExerciseSuggestion `json:"suggestions,omitempty"`
+}
+
+type ExerciseSuggestion struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type ImportRowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ImportReport describes an import, or with DryRun what an import would do.
+// WorkoutsSkipped counts workouts already present (same start time and name).
+type ImportReport struct {
+	Source           string            `json:"source"`
+	DryRun           bool              `json:"dry_run"`
+	WorkoutsFound    int               `json:"workouts_found"`
+	WorkoutsImported int               `json:"workouts_imported"`
+	WorkoutsSkipped  int               `json:"workouts_skipped"`
+	SetsImported     int               `json:"sets_imported"`
+	RowsSkipped      int               `json:"rows_skipped"`
+	Mappings         []ExerciseMapping `json:"mappings"`
+	Errors           []ImportRowError  `json:"errors"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// RestTimer counts down the rest after a set. It lives in the cache next to
+// the active workout so any of the user's devices can pick it up.
+// RemainingSeconds is computed when the timer is read and is zero once the
+// rest is over.
+type RestTimer struct {
+	WorkoutSessionID int       `json:"workout_session_id"`
+	ExerciseID       *int      `json:"exercise_id,omitempty"`
+	StartedAt        time.Time `json:"started_at"`
+	DurationSeconds  int       `json:"duration_seconds"`
+	EndsAt           time.Time `json:"ends_at"`
+	RemainingSeconds int       `json:"remaining_seconds"`
+}
+
+// SetRemaining recomputes EndsAt and RemainingSeconds as of now.
+func (t *RestTimer) SetRemaining(now time.Time) {
+	t.EndsAt = t.StartedAt.Add(time.Duration(t.DurationSeconds) * time.Second)
+	t.RemainingSeconds = 0
+	if remaining := t.EndsAt.Sub(now); remaining > 0 {
+		t.RemainingSeconds = int((remaining + time.Second - 1) / time.Second)
+	}
+}
+
+// StartRestTimerRequest starts a timer after a set of ExerciseID. Without
+// DurationSeconds the exercise's default rest is used.
+type StartRestTimerRequest struct {
+	ExerciseID      *int `json:"exercise_id,omitempty"`
+	DurationSeconds *int `json:"duration_seconds,omitempty"`
+}
+
+// ExtendRestTimerRequest adds Seconds to a running timer; negative values
+// shorten it.
+type ExtendRestTimerRequest struct {
+	Seconds int `json:"seconds"`
+}
+
+type RestTimerResponse struct {
+	Timer *RestTimer `json:"timer"`
+}
+
+// ExerciseRest is the average rest taken before working sets of an exercise.
+type ExerciseRest struct {
+	ExerciseDefinitionID int     `json:"exercise_definition_id"`
+	ExerciseName         string  `json:"exercise_name"`
+	AverageRestSeconds   float64 `json:"average_rest_seconds"`
+	SetCount             int     `json:"set_count"`
+}
+
+type RestStatsResponse struct {
+	Days      int            `json:"days"`
+	Exercises []ExerciseRest `json:"exercises"`
+}
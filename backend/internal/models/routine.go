@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+type Routine struct {
+	ID        int               `json:"id"`
+	UserID    int               `json:"user_id"`
+	Name      string            `json:"name"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Exercises []RoutineExercise `json:"exercises"`
+}
+
+type RoutineExercise struct {
+	ID                   int       `json:"id"`
+	RoutineID            int       `json:"routine_id"`
+	ExerciseDefinitionID int       `json:"exercise_definition_id"`
+	Name                 string    `json:"name"`
+	OrderIndex           int       `json:"order_index"`
+	TargetSets           int       `json:"target_sets"`
+	TargetReps           int       `json:"target_reps"`
+	TargetWeight         *float64  `json:"target_weight,omitempty"`
+	WeightUnit           string    `json:"weight_unit"`
+	IsBodyweight         bool      `json:"is_bodyweight"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+type RoutineRequest struct {
+	Name      string                 `json:"name"`
+	Exercises []RoutineExerciseInput `json:"exercises"`
+}
+
+type RoutineExerciseInput struct {
+	Name                 string   `json:"name"`
+	ExerciseDefinitionID *int     `json:"exercise_definition_id,omitempty"`
+	TargetSets           int      `json:"target_sets"`
+	TargetReps           int      `json:"target_reps"`
+	TargetWeight         *float64 `json:"target_weight,omitempty"`
+	WeightUnit           string   `json:"weight_unit,omitempty"`
+	IsBodyweight         bool     `json:"is_bodyweight"`
+}
+
+type SaveAsRoutineRequest struct {
+	Name string `json:"name"`
+}
+
+type RoutineResponse struct {
+	Routine Routine `json:"routine"`
+}
+
+type RoutinesResponse struct {
+	Routines []Routine `json:"routines"`
+}
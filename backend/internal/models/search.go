@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+const (
+	SearchHitWorkout  = "workout"
+	SearchHitExercise = "exercise"
+)
+
+// SearchHit is one match of a search. Snippet is the matched name, followed
+// by its notes if any, with the matching words wrapped in ** markers.
+// ExerciseID and ExerciseName are set for exercise hits only.
+type SearchHit struct {
+	Type         string        `json:"type"`
+	Rank         float64       `json:"rank"`
+	Snippet      string        `json:"snippet"`
+	ExerciseID   *int          `json:"exercise_id,omitempty"`
+	ExerciseName *string       `json:"exercise_name,omitempty"`
+	Workout      SearchWorkout `json:"workout"`
+}
+
+// SearchWorkout is the workout a search hit belongs to.
+type SearchWorkout struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+type SearchResponse struct {
+	Query   string      `json:"query"`
+	Results []SearchHit `json:"results"`
+	Limit   int         `json:"limit"`
+	Offset  int         `json:"offset"`
+}
@@ -2,29 +2,109 @@ package models
 
 import "time"
 
+// Estimated one-rep max formulas. A single is always its own e1RM.
+const (
+	E1RMEpley    = "epley"
+	E1RMBrzycki  = "brzycki"
+	E1RMLombardi = "lombardi"
+
+	DefaultE1RMFormula = E1RMEpley
+)
+
+func IsValidE1RMFormula(formula string) bool {
+	switch formula {
+	case E1RMEpley, E1RMBrzycki, E1RMLombardi:
+		return true
+	}
+	return false
+}
+
+const (
+	PRCategoryHeaviestSingle = "heaviest_single"
+	PRCategoryRepMax         = "rep_max"
+	PRCategoryE1RM           = "e1rm"
+	PRCategoryMostReps       = "most_reps"
+	PRCategorySessionVolume  = "session_volume"
+)
+
+// MaxRepMax is the highest rep count tracked as a rep-max PR.
+const MaxRepMax = 12
+
+// Record is the best performance in one PR category along with the set and
+// workout it came from. Set fields are empty for session-level categories.
+// Value is in kilograms so records compare across units; Weight keeps the
+// unit the set was entered in.
+type Record struct {
+	Category    string    `json:"category"`
+	RepCount    *int      `json:"rep_count,omitempty"`
+	Value       float64   `json:"value"`
+	SetID       *int      `json:"set_id,omitempty"`
+	Reps        *int      `json:"reps,omitempty"`
+	Weight      *float64  `json:"weight,omitempty"`
+	WeightUnit  string    `json:"weight_unit,omitempty"`
+	WorkoutID   int       `json:"workout_id"`
+	WorkoutName string    `json:"workout_name"`
+	AchievedAt  time.Time `json:"achieved_at"`
+}
+
+// PersonalRecord groups an exercise's records by category. HeaviestSingle is
+// the most weight moved in any one set; RepMaxes holds the heaviest set at
+// each rep count from 1 to MaxRepMax.
 type PersonalRecord struct {
-	ExerciseName string    `json:"exercise_name"`
-	MaxWeight    *float64  `json:"max_weight,omitempty"`
-	MaxReps      int       `json:"max_reps"`
-	MaxVolume    *float64  `json:"max_volume,omitempty"`
-	AchievedAt   time.Time `json:"achieved_at"`
+	ExerciseDefinitionID int      `json:"exercise_definition_id"`
+	ExerciseName         string   `json:"exercise_name"`
+	Formula              string   `json:"formula"`
+	HeaviestSingle       *Record  `json:"heaviest_single,omitempty"`
+	BestE1RM             *Record  `json:"best_e1rm,omitempty"`
+	MostReps             *Record  `json:"most_reps,omitempty"`
+	BestSessionVolume    *Record  `json:"best_session_volume,omitempty"`
+	RepMaxes             []Record `json:"rep_maxes"`
+}
+
+// PREvent records a PR broken by a completed workout. Formula is only set for
+// e1RM events. Weight-category events also keep the old and new weights as
+// entered, so they display without a round trip through kilograms.
+type PREvent struct {
+	ID                   int       `json:"id"`
+	WorkoutSessionID     int       `json:"workout_session_id"`
+	ExerciseDefinitionID int       `json:"exercise_definition_id"`
+	ExerciseName         string    `json:"exercise_name"`
+	SetID                *int      `json:"set_id,omitempty"`
+	Category             string    `json:"category"`
+	RepCount             *int      `json:"rep_count,omitempty"`
+	Formula              *string   `json:"formula,omitempty"`
+	OldValue             float64   `json:"old_value"`
+	NewValue             float64   `json:"new_value"`
+	AchievedAt           time.Time `json:"achieved_at"`
+	OldWeight            *float64  `json:"-"`
+	OldWeightUnit        *string   `json:"-"`
+	NewWeight            *float64  `json:"-"`
+	NewWeightUnit        *string   `json:"-"`
+}
+
+type PRTimelineResponse struct {
+	Events []PREvent `json:"events"`
+	Total  int       `json:"total"`
+	Unit   string    `json:"unit"`
 }
 
 type WorkoutSummary struct {
 	ID            int       `json:"id"`
 	Name          string    `json:"name"`
 	CompletedAt   time.Time `json:"completed_at"`
+	Notes         *string   `json:"notes,omitempty"`
 	ExerciseCount int       `json:"exercise_count"`
 	TotalSets     int       `json:"total_sets"`
 	TotalVolume   float64   `json:"total_volume"`
 }
 
 type WeeklySummary struct {
-	Week          string           `json:"week"`
-	TotalWorkouts int              `json:"total_workouts"`
+	Week           string          `json:"week"`
+	Unit           string          `json:"unit"`
+	TotalWorkouts  int             `json:"total_workouts"`
 	TotalExercises int             `json:"total_exercises"`
-	TotalVolume   float64          `json:"total_volume"`
-	Workouts      []WorkoutInWeek  `json:"workouts"`
+	TotalVolume    float64         `json:"total_volume"`
+	Workouts       []WorkoutInWeek `json:"workouts"`
 }
 
 type WorkoutInWeek struct {
@@ -34,23 +114,53 @@ type WorkoutInWeek struct {
 	DayOfWeek   int       `json:"day_of_week"`
 }
 
+// ProgressDataPoint summarizes one day of an exercise. MaxWeight is the
+// heaviest set in the unit it was entered in; the other figures are in
+// kilograms. Notes holds the exercise's notes from that day followed by its
+// set notes, without duplicates.
 type ProgressDataPoint struct {
-	Date      time.Time `json:"date"`
-	MaxWeight *float64  `json:"max_weight,omitempty"`
-	MaxReps   int       `json:"max_reps"`
-	Volume    float64   `json:"volume"`
+	Date          time.Time `json:"date"`
+	MaxWeight     *float64  `json:"max_weight,omitempty"`
+	MaxWeightUnit string    `json:"max_weight_unit,omitempty"`
+	MaxReps       int       `json:"max_reps"`
+	Volume        float64   `json:"volume"`
+	E1RM          *float64  `json:"e1rm,omitempty"`
+	Notes         []string  `json:"notes,omitempty"`
 }
 
 type ProgressResponse struct {
-	ExerciseName string              `json:"exercise_name"`
-	DataPoints   []ProgressDataPoint `json:"data_points"`
+	ExerciseDefinitionID int                 `json:"exercise_definition_id,omitempty"`
+	ExerciseName         string              `json:"exercise_name"`
+	Formula              string              `json:"formula"`
+	Unit                 string              `json:"unit"`
+	DataPoints           []ProgressDataPoint `json:"data_points"`
+}
+
+// HistoryFilter narrows workout history. Nil fields and an empty Name match
+// everything. To is exclusive and MinVolume is in kilograms.
+type HistoryFilter struct {
+	From                 *time.Time
+	To                   *time.Time
+	Name                 string
+	ExerciseDefinitionID *int
+	MinVolume            *float64
+}
+
+// HistoryCursor is the last workout of a history page. Clients only see it
+// encoded as an opaque next_cursor.
+type HistoryCursor struct {
+	CompletedAt time.Time `json:"completed_at"`
+	ID          int       `json:"id"`
 }
 
+// HistoryResponse has a null NextCursor on the last page.
 type HistoryResponse struct {
-	Workouts []WorkoutSummary `json:"workouts"`
-	Total    int              `json:"total"`
+	Workouts   []WorkoutSummary `json:"workouts"`
+	NextCursor *string          `json:"next_cursor"`
+	Unit       string           `json:"unit"`
 }
 
 type PRsResponse struct {
-	PRs []PersonalRecord `json:"prs"`
+	PRs  []PersonalRecord `json:"prs"`
+	Unit string           `json:"unit"`
 }
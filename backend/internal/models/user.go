@@ -3,12 +3,30 @@ package models
 import "time"
 
 type User struct {
-	ID           int       `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	Username     string    `json:"username"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID            int       `json:"id"`
+	Email         string    `json:"email"`
+	PasswordHash  string    `json:"-"`
+	Username      string    `json:"username"`
+	PreferredUnit string    `json:"preferred_unit"`
+	Timezone      string    `json:"timezone"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// UserPreferences is the per-user display configuration read on most
+// requests, cached separately from the user row.
+type UserPreferences struct {
+	PreferredUnit string `json:"preferred_unit"`
+	Timezone      string `json:"timezone"`
+}
+
+type UpdatePreferencesRequest struct {
+	PreferredUnit *string `json:"preferred_unit,omitempty"`
+	Timezone      *string `json:"timezone,omitempty"`
+}
+
+type UserResponse struct {
+	User User `json:"user"`
 }
 
 type RegisterRequest struct {
@@ -23,6 +41,34 @@ type LoginRequest struct {
 }
 
 type AuthResponse struct {
-	User  User   `json:"user"`
-	Token string `json:"token"`
+	User         User   `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type RefreshToken struct {
+	ID           int
+	UserID       int
+	FamilyID     string
+	TokenHash    string
+	AccessJTI    *string
+	ExpiresAt    time.Time
+	RevokedAt    *time.Time
+	ReplacedByID *int
+	CreatedAt    time.Time
+}
+
+type TokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
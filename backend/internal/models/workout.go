@@ -2,65 +2,260 @@ package models
 
 import "time"
 
+const (
+	WorkoutStatusActive    = "active"
+	WorkoutStatusPaused    = "paused"
+	WorkoutStatusCompleted = "completed"
+	WorkoutStatusAbandoned = "abandoned"
+)
+
+// WorkoutSession is one workout. ActiveSeconds is the time from start to
+// completion, abandonment or now, less any time spent paused.
 type WorkoutSession struct {
-	ID          int       `json:"id"`
-	UserID      int       `json:"user_id"`
-	Name        string    `json:"name"`
-	Status      string    `json:"status"`
-	StartedAt   time.Time `json:"started_at"`
-	CompletedAt *time.Time `json:"completed_at,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Exercises   []Exercise `json:"exercises,omitempty"`
+	ID            int            `json:"id"`
+	UserID        int            `json:"user_id"`
+	Name          string         `json:"name"`
+	Status        string         `json:"status"`
+	StartedAt     time.Time      `json:"started_at"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
+	AbandonedAt   *time.Time     `json:"abandoned_at,omitempty"`
+	ActiveSeconds int            `json:"active_seconds"`
+	Notes         *string        `json:"notes,omitempty"`
+	Version       int            `json:"version"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	Pauses        []WorkoutPause `json:"pauses,omitempty"`
+	Exercises     []Exercise     `json:"exercises,omitempty"`
+}
+
+// WorkoutPause is one pause of a workout; ResumedAt is nil while it lasts.
+type WorkoutPause struct {
+	ID        int        `json:"id"`
+	PausedAt  time.Time  `json:"paused_at"`
+	ResumedAt *time.Time `json:"resumed_at,omitempty"`
+}
+
+// SetActiveSeconds recomputes ActiveSeconds from Pauses, treating a workout
+// that is still going as ending at now.
+func (w *WorkoutSession) SetActiveSeconds(now time.Time) {
+	end := now
+	if w.CompletedAt != nil {
+		end = *w.CompletedAt
+	} else if w.AbandonedAt != nil {
+		end = *w.AbandonedAt
+	}
+
+	active := end.Sub(w.StartedAt)
+	for _, pause := range w.Pauses {
+		resumed := end
+		if pause.ResumedAt != nil && pause.ResumedAt.Before(end) {
+			resumed = *pause.ResumedAt
+		}
+		if resumed.After(pause.PausedAt) {
+			active -= resumed.Sub(pause.PausedAt)
+		}
+	}
+
+	if active < 0 {
+		active = 0
+	}
+	w.ActiveSeconds = int(active / time.Second)
 }
 
 type Exercise struct {
-	ID               int       `json:"id"`
-	WorkoutSessionID int       `json:"workout_session_id"`
-	Name             string    `json:"name"`
-	OrderIndex       int       `json:"order_index"`
-	CreatedAt        time.Time `json:"created_at"`
-	UpdatedAt        time.Time `json:"updated_at"`
-	Sets             []Set     `json:"sets,omitempty"`
+	ID                   int       `json:"id"`
+	WorkoutSessionID     int       `json:"workout_session_id"`
+	ExerciseDefinitionID int       `json:"exercise_definition_id"`
+	Name                 string    `json:"name"`
+	OrderIndex           int       `json:"order_index"`
+	Notes                *string   `json:"notes,omitempty"`
+	Version              int       `json:"version"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+	Sets                 []Set     `json:"sets,omitempty"`
+}
+
+const (
+	SetTypeNormal  = "normal"
+	SetTypeWarmup  = "warmup"
+	SetTypeDrop    = "drop"
+	SetTypeAMRAP   = "amrap"
+	SetTypeFailure = "failure"
+)
+
+// IsValidSetType reports whether t is one of the SetType constants.
+func IsValidSetType(t string) bool {
+	switch t {
+	case SetTypeNormal, SetTypeWarmup, SetTypeDrop, SetTypeAMRAP, SetTypeFailure:
+		return true
+	}
+	return false
 }
 
+// MaxRestSeconds caps rest_seconds on sets and the rest timer.
+const MaxRestSeconds = 3600
+
+// Set is one logged set. Warm-up sets are kept in the log but never count
+// toward PRs or volume. RestSeconds is the rest taken before the set.
 type Set struct {
 	ID           int       `json:"id"`
 	ExerciseID   int       `json:"exercise_id"`
 	SetNumber    int       `json:"set_number"`
 	Reps         int       `json:"reps"`
 	Weight       *float64  `json:"weight,omitempty"`
+	WeightUnit   string    `json:"weight_unit"`
 	IsBodyweight bool      `json:"is_bodyweight"`
+	IsCompleted  bool      `json:"is_completed"`
+	SetType      string    `json:"set_type"`
+	RPE          *float64  `json:"rpe,omitempty"`
+	RIR          *int      `json:"rir,omitempty"`
+	Tempo        *string   `json:"tempo,omitempty"`
+	RestSeconds  *int      `json:"rest_seconds,omitempty"`
+	Notes        *string   `json:"notes,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// Values for the replace query parameter of POST /workouts, which says what
+// to do with a workout that is still unfinished.
+const (
+	ReplaceComplete = "complete"
+	ReplaceAbandon  = "abandon"
+)
+
 type CreateWorkoutRequest struct {
-	Name string `json:"name"`
+	Name      string `json:"name"`
+	RoutineID *int   `json:"routine_id,omitempty"`
+}
+
+// LogWorkoutRequest records a workout that has already happened, with all of
+// its exercises and sets, in one request.
+type LogWorkoutRequest struct {
+	Name        string                  `json:"name"`
+	StartedAt   *time.Time              `json:"started_at"`
+	CompletedAt *time.Time              `json:"completed_at"`
+	Notes       *string                 `json:"notes,omitempty"`
+	Exercises   []CreateExerciseRequest `json:"exercises"`
+}
+
+// UpdateWorkoutRequest is a partial update. CompletedAt can only be changed
+// on completed workouts. Empty Notes clear them.
+type UpdateWorkoutRequest struct {
+	Name        *string    `json:"name,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	Notes       *string    `json:"notes,omitempty"`
 }
 
+// MaxNotesLength caps notes on workouts, exercises and sets, in characters.
+const MaxNotesLength = 2000
+
+const (
+	RevisionUpdateWorkout  = "update_workout"
+	RevisionAddExercise    = "add_exercise"
+	RevisionUpdateExercise = "update_exercise"
+	RevisionDeleteExercise = "delete_exercise"
+	RevisionAddSet         = "add_set"
+	RevisionUpdateSet      = "update_set"
+	RevisionDeleteSet      = "delete_set"
+	RevisionReorderSets    = "reorder_sets"
+)
+
+// WorkoutRevision records one edit to a completed workout, with the whole
+// workout as it was before and after.
+type WorkoutRevision struct {
+	ID               int            `json:"id"`
+	WorkoutSessionID int            `json:"workout_session_id"`
+	UserID           int            `json:"user_id"`
+	Action           string         `json:"action"`
+	Before           WorkoutSession `json:"before"`
+	After            WorkoutSession `json:"after"`
+	CreatedAt        time.Time      `json:"created_at"`
+}
+
+type WorkoutRevisionsResponse struct {
+	Revisions []WorkoutRevision `json:"revisions"`
+	Unit      string            `json:"unit"`
+}
+
+// CreateWorkoutResponse.PREvents holds the records set by the workout that
+// replace=complete ended, if any.
 type CreateWorkoutResponse struct {
-	Workout WorkoutSession `json:"workout"`
+	Workout  WorkoutSession `json:"workout"`
+	PREvents []PREvent      `json:"pr_events,omitempty"`
 }
 
 type GetWorkoutResponse struct {
 	Workout WorkoutSession `json:"workout"`
 }
 
+type CompleteWorkoutResponse struct {
+	Workout  WorkoutSession `json:"workout"`
+	PREvents []PREvent      `json:"pr_events"`
+	Unit     string         `json:"unit"`
+}
+
 type CreateExerciseRequest struct {
-	Name string    `json:"name"`
-	Sets []SetInput `json:"sets"`
+	Name                 string     `json:"name"`
+	ExerciseDefinitionID *int       `json:"exercise_definition_id,omitempty"`
+	Notes                *string    `json:"notes,omitempty"`
+	Sets                 []SetInput `json:"sets"`
 }
 
+// SetInput.WeightUnit defaults to the user's preferred unit.
 type SetInput struct {
 	Reps         int      `json:"reps"`
 	Weight       *float64 `json:"weight,omitempty"`
+	WeightUnit   string   `json:"weight_unit,omitempty"`
 	IsBodyweight bool     `json:"is_bodyweight"`
+	IsCompleted  *bool    `json:"is_completed,omitempty"`
+	SetType      string   `json:"set_type,omitempty"`
+	RPE          *float64 `json:"rpe,omitempty"`
+	RIR          *int     `json:"rir,omitempty"`
+	Tempo        *string  `json:"tempo,omitempty"`
+	RestSeconds  *int     `json:"rest_seconds,omitempty"`
+	Notes        *string  `json:"notes,omitempty"`
 }
 
+// UpdateExerciseRequest replaces the sets when any are given. Empty Notes
+// clear them.
 type UpdateExerciseRequest struct {
-	Name string     `json:"name,omitempty"`
-	Sets []SetInput `json:"sets,omitempty"`
+	Name                 string     `json:"name,omitempty"`
+	ExerciseDefinitionID *int       `json:"exercise_definition_id,omitempty"`
+	Notes                *string    `json:"notes,omitempty"`
+	Sets                 []SetInput `json:"sets,omitempty"`
+}
+
+// CreateSetRequest appends a set to an exercise. Position is 1-based; when
+// given, the set is inserted there and later sets are renumbered.
+type CreateSetRequest struct {
+	SetInput
+	Position *int `json:"position,omitempty"`
+}
+
+// UpdateSetRequest is a partial update; omitted fields are left unchanged.
+// SetNumber moves the set within its exercise. Empty Notes clear them.
+type UpdateSetRequest struct {
+	Reps         *int     `json:"reps,omitempty"`
+	Weight       *float64 `json:"weight,omitempty"`
+	WeightUnit   *string  `json:"weight_unit,omitempty"`
+	IsBodyweight *bool    `json:"is_bodyweight,omitempty"`
+	IsCompleted  *bool    `json:"is_completed,omitempty"`
+	SetType      *string  `json:"set_type,omitempty"`
+	RPE          *float64 `json:"rpe,omitempty"`
+	RIR          *int     `json:"rir,omitempty"`
+	Tempo        *string  `json:"tempo,omitempty"`
+	RestSeconds  *int     `json:"rest_seconds,omitempty"`
+	Notes        *string  `json:"notes,omitempty"`
+	SetNumber    *int     `json:"set_number,omitempty"`
+}
+
+type ReorderSetsRequest struct {
+	SetIDs []int `json:"set_ids"`
+}
+
+type SetResponse struct {
+	Set Set `json:"set"`
 }
 
 type CreateExerciseResponse struct {
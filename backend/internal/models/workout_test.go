@@ -0,0 +1,54 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetActiveSeconds(t *testing.T) {
+	start := time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	ptr := func(t time.Time) *time.Time { return &t }
+	pause := func(from, to int) WorkoutPause {
+		return WorkoutPause{PausedAt: at(from), ResumedAt: ptr(at(to))}
+	}
+	openPause := func(from int) WorkoutPause {
+		return WorkoutPause{PausedAt: at(from)}
+	}
+
+	tests := []struct {
+		name        string
+		completedAt *time.Time
+		abandonedAt *time.Time
+		pauses      []WorkoutPause
+		now         time.Time
+		want        time.Duration
+	}{
+		{"running, no pauses", nil, nil, nil, at(30), 30 * time.Minute},
+		{"running after a pause", nil, nil, []WorkoutPause{pause(10, 15)}, at(30), 25 * time.Minute},
+		{"paused now", nil, nil, []WorkoutPause{pause(5, 10), openPause(20)}, at(30), 15 * time.Minute},
+		{"completed ignores now", ptr(at(60)), nil, []WorkoutPause{pause(10, 20)}, at(600), 50 * time.Minute},
+		{"completed while paused", ptr(at(60)), nil, []WorkoutPause{pause(50, 90)}, at(600), 50 * time.Minute},
+		{"open pause when completed", ptr(at(60)), nil, []WorkoutPause{openPause(40)}, at(600), 40 * time.Minute},
+		{"pause after completion", ptr(at(60)), nil, []WorkoutPause{pause(70, 80)}, at(600), 60 * time.Minute},
+		{"abandoned", nil, ptr(at(45)), []WorkoutPause{openPause(30)}, at(600), 30 * time.Minute},
+		{"paused longer than it ran", ptr(at(10)), nil, []WorkoutPause{pause(0, 10), pause(0, 10)}, at(600), 0},
+		{"clock behind the start", nil, nil, nil, at(-5), 0},
+		{"partial seconds truncate", nil, nil, nil, start.Add(90*time.Second + 999*time.Millisecond), 90 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &WorkoutSession{
+				StartedAt:   start,
+				CompletedAt: tt.completedAt,
+				AbandonedAt: tt.abandonedAt,
+				Pauses:      tt.pauses,
+			}
+			w.SetActiveSeconds(tt.now)
+			if want := int(tt.want / time.Second); w.ActiveSeconds != want {
+				t.Fatalf("ActiveSeconds = %d, want %d", w.ActiveSeconds, want)
+			}
+		})
+	}
+}
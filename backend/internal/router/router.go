@@ -5,45 +5,93 @@ import (
 	"net/http"
 
 	"github.com/damion-14/cadence/backend/internal/auth"
-	"github.com/damion-14/cadence/backend/internal/cache"
 	"github.com/damion-14/cadence/backend/internal/config"
 	"github.com/damion-14/cadence/backend/internal/handlers"
-	"github.com/redis/go-redis/v9"
 )
 
 type Dependencies struct {
 	DB              *sql.DB
-	Redis           *redis.Client
-	Cache           *cache.Cache
+	Denylist        auth.Denylist
 	Config          *config.Config
 	AuthHandler     *handlers.AuthHandler
 	WorkoutHandler  *handlers.WorkoutHandler
 	ExerciseHandler *handlers.ExerciseHandler
+	SetHandler      *handlers.SetHandler
 	StatsHandler    *handlers.StatsHandler
+	RoutineHandler  *handlers.RoutineHandler
+	CatalogHandler  *handlers.CatalogHandler
+	UserHandler     *handlers.UserHandler
+	ExportHandler   *handlers.ExportHandler
+	ImportHandler   *handlers.ImportHandler
+	SearchHandler   *handlers.SearchHandler
 }
 
 func NewRouter(deps *Dependencies) *http.ServeMux {
 	mux := http.NewServeMux()
 
-	authMiddleware := auth.Middleware(deps.Config.JWT.Secret)
+	authMiddleware := auth.Middleware(deps.Config.JWT.Secret, deps.Denylist)
 
 	mux.HandleFunc("POST /api/v1/auth/register", deps.AuthHandler.Register)
 	mux.HandleFunc("POST /api/v1/auth/login", deps.AuthHandler.Login)
+	mux.HandleFunc("POST /api/v1/auth/refresh", deps.AuthHandler.Refresh)
+	mux.Handle("POST /api/v1/auth/logout", authMiddleware(http.HandlerFunc(deps.AuthHandler.Logout)))
+	mux.Handle("POST /api/v1/auth/logout-all", authMiddleware(http.HandlerFunc(deps.AuthHandler.LogoutAll)))
+
+	mux.Handle("GET /api/v1/users/me", authMiddleware(http.HandlerFunc(deps.UserHandler.GetMe)))
+	mux.Handle("PATCH /api/v1/users/me/preferences", authMiddleware(http.HandlerFunc(deps.UserHandler.UpdatePreferences)))
 
 	mux.Handle("POST /api/v1/workouts", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.Create)))
+	mux.Handle("POST /api/v1/workouts/log", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.Log)))
 	mux.Handle("GET /api/v1/workouts/active", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.GetActive)))
+	mux.Handle("GET /api/v1/workouts/active/stream", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.Stream)))
 	mux.Handle("GET /api/v1/workouts/{id}", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.GetByID)))
+	mux.Handle("PATCH /api/v1/workouts/{id}", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.Update)))
+	mux.Handle("GET /api/v1/workouts/{id}/revisions", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.Revisions)))
 	mux.Handle("POST /api/v1/workouts/{id}/complete", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.Complete)))
+	mux.Handle("POST /api/v1/workouts/{id}/pause", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.Pause)))
+	mux.Handle("POST /api/v1/workouts/{id}/resume", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.Resume)))
+	mux.Handle("POST /api/v1/workouts/{id}/abandon", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.Abandon)))
 	mux.Handle("DELETE /api/v1/workouts/{id}", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.Delete)))
+	mux.Handle("GET /api/v1/workouts/{id}/rest-timer", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.GetRestTimer)))
+	mux.Handle("POST /api/v1/workouts/{id}/rest-timer", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.StartRestTimer)))
+	mux.Handle("POST /api/v1/workouts/{id}/rest-timer/extend", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.ExtendRestTimer)))
+	mux.Handle("DELETE /api/v1/workouts/{id}/rest-timer", authMiddleware(http.HandlerFunc(deps.WorkoutHandler.StopRestTimer)))
+	mux.Handle("POST /api/v1/workouts/{id}/save-as-routine", authMiddleware(http.HandlerFunc(deps.RoutineHandler.SaveFromWorkout)))
 
 	mux.Handle("POST /api/v1/workouts/{workoutId}/exercises", authMiddleware(http.HandlerFunc(deps.ExerciseHandler.Create)))
 	mux.Handle("PUT /api/v1/workouts/{workoutId}/exercises/{id}", authMiddleware(http.HandlerFunc(deps.ExerciseHandler.Update)))
 	mux.Handle("DELETE /api/v1/workouts/{workoutId}/exercises/{id}", authMiddleware(http.HandlerFunc(deps.ExerciseHandler.Delete)))
 
+	mux.Handle("POST /api/v1/workouts/{workoutId}/exercises/{exerciseId}/sets", authMiddleware(http.HandlerFunc(deps.SetHandler.Create)))
+	mux.Handle("PUT /api/v1/workouts/{workoutId}/exercises/{exerciseId}/sets/order", authMiddleware(http.HandlerFunc(deps.SetHandler.Reorder)))
+	mux.Handle("PATCH /api/v1/workouts/{workoutId}/exercises/{exerciseId}/sets/{setId}", authMiddleware(http.HandlerFunc(deps.SetHandler.Update)))
+	mux.Handle("DELETE /api/v1/workouts/{workoutId}/exercises/{exerciseId}/sets/{setId}", authMiddleware(http.HandlerFunc(deps.SetHandler.Delete)))
+
+	mux.Handle("GET /api/v1/exercise-catalog", authMiddleware(http.HandlerFunc(deps.CatalogHandler.Search)))
+	mux.Handle("POST /api/v1/exercise-catalog", authMiddleware(http.HandlerFunc(deps.CatalogHandler.Create)))
+	mux.Handle("GET /api/v1/exercise-catalog/{id}", authMiddleware(http.HandlerFunc(deps.CatalogHandler.GetByID)))
+
+	mux.Handle("GET /api/v1/routines", authMiddleware(http.HandlerFunc(deps.RoutineHandler.List)))
+	mux.Handle("POST /api/v1/routines", authMiddleware(http.HandlerFunc(deps.RoutineHandler.Create)))
+	mux.Handle("GET /api/v1/routines/{id}", authMiddleware(http.HandlerFunc(deps.RoutineHandler.GetByID)))
+	mux.Handle("PUT /api/v1/routines/{id}", authMiddleware(http.HandlerFunc(deps.RoutineHandler.Update)))
+	mux.Handle("DELETE /api/v1/routines/{id}", authMiddleware(http.HandlerFunc(deps.RoutineHandler.Delete)))
+
 	mux.Handle("GET /api/v1/history", authMiddleware(http.HandlerFunc(deps.StatsHandler.GetHistory)))
 	mux.Handle("GET /api/v1/stats/prs", authMiddleware(http.HandlerFunc(deps.StatsHandler.GetPRs)))
+	mux.Handle("GET /api/v1/stats/prs/timeline", authMiddleware(http.HandlerFunc(deps.StatsHandler.GetPRTimeline)))
 	mux.Handle("GET /api/v1/stats/weekly", authMiddleware(http.HandlerFunc(deps.StatsHandler.GetWeeklySummary)))
 	mux.Handle("GET /api/v1/stats/progress/{exerciseName}", authMiddleware(http.HandlerFunc(deps.StatsHandler.GetProgress)))
+	mux.Handle("GET /api/v1/stats/rest", authMiddleware(http.HandlerFunc(deps.StatsHandler.GetRest)))
+
+	mux.Handle("GET /api/v1/search", authMiddleware(http.HandlerFunc(deps.SearchHandler.Search)))
+
+	mux.Handle("GET /api/v1/export", authMiddleware(http.HandlerFunc(deps.ExportHandler.Export)))
+	mux.Handle("POST /api/v1/export/jobs", authMiddleware(http.HandlerFunc(deps.ExportHandler.CreateJob)))
+	mux.Handle("GET /api/v1/export/jobs/{id}", authMiddleware(http.HandlerFunc(deps.ExportHandler.GetJob)))
+	mux.Handle("GET /api/v1/export/jobs/{id}/download", authMiddleware(http.HandlerFunc(deps.ExportHandler.DownloadJob)))
+
+	mux.Handle("POST /api/v1/import", authMiddleware(http.HandlerFunc(deps.ImportHandler.Import)))
 
 	mux.HandleFunc("GET /health", healthCheck)
 
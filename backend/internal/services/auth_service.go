@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/auth"
+	"github.com/damion-14/cadence/backend/internal/cache"
+	"github.com/damion-14/cadence/backend/internal/config"
+	"github.com/damion-14/cadence/backend/internal/database/queries"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+// Cached denylist answers for an access token ID.
+const (
+	tokenRevoked    = "1"
+	tokenNotRevoked = "0"
+)
+
+type AuthService struct {
+	db           *sql.DB
+	userQueries  *queries.UserQueries
+	tokenQueries *queries.TokenQueries
+	cache        cache.Cache
+	jwtConfig    config.JWTConfig
+}
+
+func NewAuthService(db *sql.DB, cacheClient cache.Cache, jwtConfig config.JWTConfig) *AuthService {
+	return &AuthService{
+		db:           db,
+		userQueries:  queries.NewUserQueries(db),
+		tokenQueries: queries.NewTokenQueries(db),
+		cache:        cacheClient,
+		jwtConfig:    jwtConfig,
+	}
+}
+
+// IssueTokens starts a new refresh token family for a fresh login.
+func (s *AuthService) IssueTokens(ctx context.Context, user *models.User) (*models.TokenPair, error) {
+	accessToken, jti, err := auth.GenerateToken(user.ID, user.Email, s.jwtConfig.Secret, s.accessTTL())
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, refreshHash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.tokenQueries.CreateRefreshToken(ctx, user.ID, uuid.New().String(), refreshHash, jti, time.Now().Add(s.refreshTTL()))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokenPair(accessToken, refreshToken), nil
+}
+
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	stored, err := s.tokenQueries.GetRefreshTokenByHash(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+
+	// A revoked token being presented again means it was either stolen or
+	// replayed; either way nothing in its family can be trusted any more.
+	if stored.RevokedAt != nil {
+		if err := s.revokeFamily(ctx, stored.FamilyID); err != nil {
+			fmt.Printf("Failed to revoke token family: %v\n", err)
+		}
+		return nil, fmt.Errorf("refresh token reuse detected")
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, fmt.Errorf("refresh token expired")
+	}
+
+	user, err := s.userQueries.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, jti, err := auth.GenerateToken(user.ID, user.Email, s.jwtConfig.Secret, s.accessTTL())
+	if err != nil {
+		return nil, err
+	}
+
+	newRefreshToken, newRefreshHash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.tokenQueries.RotateRefreshToken(ctx, stored.ID, newRefreshHash, jti, time.Now().Add(s.refreshTTL())); err != nil {
+		if errors.Is(err, queries.ErrRefreshTokenUsed) {
+			if err := s.revokeFamily(ctx, stored.FamilyID); err != nil {
+				fmt.Printf("Failed to revoke token family: %v\n", err)
+			}
+			return nil, fmt.Errorf("refresh token reuse detected")
+		}
+		return nil, err
+	}
+
+	return s.tokenPair(accessToken, newRefreshToken), nil
+}
+
+func (s *AuthService) Logout(ctx context.Context, userID int, accessJTI, refreshToken string) error {
+	return s.revoke(ctx, func(tokenQueries *queries.TokenQueries) ([]string, error) {
+		jtis := []string{accessJTI}
+		if refreshToken == "" {
+			return jtis, nil
+		}
+
+		stored, err := tokenQueries.GetRefreshTokenByHash(ctx, auth.HashRefreshToken(refreshToken))
+		if err != nil {
+			return nil, err
+		}
+
+		if stored.UserID != userID {
+			return nil, fmt.Errorf("unauthorized")
+		}
+
+		familyJTIs, err := tokenQueries.RevokeTokenFamily(ctx, stored.FamilyID, time.Now().Add(-s.accessTTL()))
+		if err != nil {
+			return nil, err
+		}
+
+		return append(jtis, familyJTIs...), nil
+	})
+}
+
+func (s *AuthService) LogoutAll(ctx context.Context, userID int, accessJTI string) error {
+	return s.revoke(ctx, func(tokenQueries *queries.TokenQueries) ([]string, error) {
+		jtis, err := tokenQueries.RevokeUserTokens(ctx, userID, time.Now().Add(-s.accessTTL()))
+		if err != nil {
+			return nil, err
+		}
+
+		return append(jtis, accessJTI), nil
+	})
+}
+
+func (s *AuthService) revokeFamily(ctx context.Context, familyID string) error {
+	return s.revoke(ctx, func(tokenQueries *queries.TokenQueries) ([]string, error) {
+		return tokenQueries.RevokeTokenFamily(ctx, familyID, time.Now().Add(-s.accessTTL()))
+	})
+}
+
+// revoke runs fn in a transaction and denylists the access token IDs it
+// returns in that same transaction, so a revocation is never half applied.
+// The cache is only told after the commit; if that fails the revocation
+// still holds and auth.Middleware finds it in the database once any cached
+// "not revoked" answer expires.
+func (s *AuthService) revoke(ctx context.Context, fn func(tokenQueries *queries.TokenQueries) ([]string, error)) error {
+	var jtis []string
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		tokenQueries := queries.NewTokenQueries(tx)
+
+		revoked, err := fn(tokenQueries)
+		if err != nil {
+			return err
+		}
+
+		for _, jti := range revoked {
+			if jti != "" {
+				jtis = append(jtis, jti)
+			}
+		}
+
+		return tokenQueries.DenyAccessTokens(ctx, jtis, time.Now().Add(s.accessTTL()))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, jti := range jtis {
+		if err := s.cache.Set(ctx, cache.GetRevokedTokenKey(jti), tokenRevoked, s.accessTTL()); err != nil {
+			fmt.Printf("Failed to cache revoked token: %v\n", err)
+		}
+	}
+
+	return nil
+}
+
+// IsTokenRevoked reports whether an access token was revoked. The database
+// is the source of truth; the cache remembers its answers, "not revoked"
+// only briefly, so most requests never reach it.
+func (s *AuthService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	key := cache.GetRevokedTokenKey(jti)
+
+	if value, err := s.cache.Get(ctx, key); err == nil {
+		return value == tokenRevoked, nil
+	}
+
+	revoked, err := s.tokenQueries.IsAccessTokenRevoked(ctx, jti)
+	if err != nil {
+		return false, err
+	}
+
+	value, ttl := tokenNotRevoked, cache.TTLTokenNotRevoked
+	if revoked {
+		value, ttl = tokenRevoked, s.accessTTL()
+	}
+	s.cache.Set(ctx, key, value, ttl)
+
+	return revoked, nil
+}
+
+func (s *AuthService) tokenPair(accessToken, refreshToken string) *models.TokenPair {
+	return &models.TokenPair{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(s.accessTTL().Seconds()),
+	}
+}
+
+func (s *AuthService) accessTTL() time.Duration {
+	return time.Duration(s.jwtConfig.AccessExpiryMinutes) * time.Minute
+}
+
+func (s *AuthService) refreshTTL() time.Duration {
+	return time.Duration(s.jwtConfig.RefreshExpiryHours) * time.Hour
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/damion-14/cadence/backend/internal/database/queries"
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+const (
+	defaultCatalogLimit = 50
+	maxCatalogLimit     = 200
+)
+
+type CatalogService struct {
+	catalogQueries *queries.CatalogQueries
+}
+
+func NewCatalogService(db *sql.DB) *CatalogService {
+	return &CatalogService{
+		catalogQueries: queries.NewCatalogQueries(db),
+	}
+}
+
+func (s *CatalogService) Search(ctx context.Context, userID int, search string, limit int) ([]models.ExerciseDefinition, error) {
+	if limit <= 0 || limit > maxCatalogLimit {
+		limit = defaultCatalogLimit
+	}
+
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return s.catalogQueries.ListDefinitions(ctx, userID, limit)
+	}
+
+	return s.catalogQueries.SearchDefinitions(ctx, userID, search, limit)
+}
+
+func (s *CatalogService) GetDefinition(ctx context.Context, userID, definitionID int) (*models.ExerciseDefinition, error) {
+	return resolveExercise(ctx, s.catalogQueries, userID, &definitionID, "")
+}
+
+func (s *CatalogService) CreateCustomDefinition(ctx context.Context, userID int, req models.CreateExerciseDefinitionRequest) (*models.ExerciseDefinition, error) {
+	if existing, err := s.catalogQueries.FindDefinitionByName(ctx, userID, req.Name); err == nil {
+		return nil, fmt.Errorf("exercise %q already exists in the catalog", existing.Name)
+	}
+
+	return s.catalogQueries.CreateDefinition(ctx, userID, req)
+}
+
+// ResolveExercise maps what the client sent onto a catalog definition. An
+// explicit definition ID wins; otherwise the name is matched against names
+// and aliases, and an unknown name becomes a new custom definition so that
+// free-text entry keeps working.
+func (s *CatalogService) ResolveExercise(ctx context.Context, userID int, definitionID *int, name string) (*models.ExerciseDefinition, error) {
+	return resolveExercise(ctx, s.catalogQueries, userID, definitionID, name)
+}
+
+// resolveExercise is shared with services that need to resolve definitions
+// inside their own transaction.
+func resolveExercise(ctx context.Context, catalogQueries *queries.CatalogQueries, userID int, definitionID *int, name string) (*models.ExerciseDefinition, error) {
+	if definitionID != nil {
+		definition, err := catalogQueries.GetDefinitionByID(ctx, *definitionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if definition.UserID != nil && *definition.UserID != userID {
+			return nil, fmt.Errorf("exercise definition not found")
+		}
+
+		return definition, nil
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("exercise name is required")
+	}
+
+	definition, err := catalogQueries.FindDefinitionByName(ctx, userID, name)
+	if err == nil {
+		return definition, nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		return nil, err
+	}
+
+	return catalogQueries.CreateDefinition(ctx, userID, models.CreateExerciseDefinitionRequest{
+		Name:           name,
+		Aliases:        []string{},
+		PrimaryMuscles: []string{},
+		Equipment:      "other",
+		UnitType:       "weight_reps",
+	})
+}
+
+// FindDefinition looks up a definition by name or alias without creating one.
+func (s *CatalogService) FindDefinition(ctx context.Context, userID int, name string) (*models.ExerciseDefinition, error) {
+	return s.catalogQueries.FindDefinitionByName(ctx, userID, strings.TrimSpace(name))
+}
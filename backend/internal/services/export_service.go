@@ -0,0 +1,185 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/config"
+	"github.com/damion-14/cadence/backend/internal/database/queries"
+	"github.com/damion-14/cadence/backend/internal/export"
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+// exportJobTimeout bounds a background export. Jobs still unfinished after
+// this long were interrupted and no longer block a new request.
+const exportJobTimeout = 30 * time.Minute
+
+type ExportService struct {
+	db            *sql.DB
+	exportQueries *queries.ExportQueries
+	dir           string
+	retention     time.Duration
+}
+
+func NewExportService(db *sql.DB, exportConfig config.ExportConfig) *ExportService {
+	return &ExportService{
+		db:            db,
+		exportQueries: queries.NewExportQueries(db),
+		dir:           exportConfig.Dir,
+		retention:     time.Duration(exportConfig.RetentionHours) * time.Hour,
+	}
+}
+
+// Export streams the user's completed workouts to w in format.
+func (s *ExportService) Export(ctx context.Context, userID int, format string, w io.Writer, opts export.Options) error {
+	writer, err := export.NewWriter(w, format, opts)
+	if err != nil {
+		return err
+	}
+
+	err = s.exportQueries.StreamCompletedWorkouts(ctx, userID, writer.WriteWorkout)
+	if err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// StartExportJob queues a background export of every format into one zip
+// archive. A user has at most one job in flight; asking again returns it.
+func (s *ExportService) StartExportJob(ctx context.Context, userID int, opts export.Options) (*models.ExportJob, error) {
+	s.purgeExpiredArchives(ctx)
+
+	if err := s.exportQueries.FailStaleExportJobs(ctx, userID, time.Now().Add(-exportJobTimeout)); err != nil {
+		return nil, err
+	}
+
+	// Losing the insert to a concurrent request means its job is returned
+	// instead. That job can finish before it is read, so try once more.
+	for attempt := 0; attempt < 2; attempt++ {
+		job, err := s.exportQueries.CreateExportJob(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil {
+			go s.runExportJob(job.ID, userID, opts)
+			return job, nil
+		}
+
+		job, err = s.exportQueries.GetUnfinishedExportJob(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil {
+			return job, nil
+		}
+	}
+
+	return nil, fmt.Errorf("failed to start export job")
+}
+
+func (s *ExportService) GetExportJob(ctx context.Context, userID, jobID int) (*models.ExportJob, error) {
+	job, err := s.exportQueries.GetExportJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.UserID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	return job, nil
+}
+
+// OpenExportArchive opens a finished job's archive for download. The archive
+// lives in Postgres, so any instance can serve it.
+func (s *ExportService) OpenExportArchive(ctx context.Context, userID, jobID int) (io.ReadSeeker, *models.ExportJob, error) {
+	job, err := s.GetExportJob(ctx, userID, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if job.Status != models.ExportJobCompleted {
+		return nil, nil, fmt.Errorf("export is not ready")
+	}
+
+	if job.ArchiveOID == nil || job.SizeBytes == nil || (job.ExpiresAt != nil && time.Now().After(*job.ExpiresAt)) {
+		return nil, nil, fmt.Errorf("export has expired")
+	}
+
+	return s.exportQueries.OpenExportArchive(ctx, *job.ArchiveOID, *job.SizeBytes), job, nil
+}
+
+func (s *ExportService) runExportJob(jobID, userID int, opts export.Options) {
+	ctx, cancel := context.WithTimeout(context.Background(), exportJobTimeout)
+	defer cancel()
+
+	if err := s.exportQueries.MarkExportJobRunning(ctx, jobID); err != nil {
+		fmt.Printf("Failed to start export job %d: %v\n", jobID, err)
+	}
+
+	if err := s.writeArchive(ctx, jobID, userID, opts); err != nil {
+		fmt.Printf("Failed to run export job %d: %v\n", jobID, err)
+		if err := s.exportQueries.FailExportJob(ctx, jobID, "export failed"); err != nil {
+			fmt.Printf("Failed to mark export job %d failed: %v\n", jobID, err)
+		}
+	}
+}
+
+// writeArchive builds the zip in a scratch file under dir, then copies it
+// into Postgres in the same transaction that completes the job, so a crash
+// never leaves a truncated archive behind.
+func (s *ExportService) writeArchive(ctx context.Context, jobID, userID int, opts export.Options) error {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "export-*.zip.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	archive := zip.NewWriter(tmp)
+	now := time.Now().In(opts.Location)
+	for _, format := range export.Formats {
+		entry, err := archive.Create(export.FileName(format, now))
+		if err != nil {
+			return err
+		}
+		if err := s.Export(ctx, userID, format, entry, opts); err != nil {
+			return err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return err
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		exportQueries := queries.NewExportQueries(tx)
+
+		oid, size, err := exportQueries.WriteExportArchive(ctx, tmp)
+		if err != nil {
+			return err
+		}
+
+		return exportQueries.CompleteExportJob(ctx, jobID, oid, size, time.Now().Add(s.retention))
+	})
+}
+
+func (s *ExportService) purgeExpiredArchives(ctx context.Context) {
+	if err := s.exportQueries.ClearExpiredExportArchives(ctx); err != nil {
+		fmt.Printf("Failed to clear expired exports: %v\n", err)
+	}
+}
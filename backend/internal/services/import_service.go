@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/cache"
+	"github.com/damion-14/cadence/backend/internal/database/queries"
+	"github.com/damion-14/cadence/backend/internal/importer"
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+// importBatchSize is how many workouts are written per batch of inserts.
+const importBatchSize = 200
+
+const maxImportSuggestions = 3
+
+// equipmentSuffix matches the "(Barbell)" style suffix Strong and Hevy put on
+// exercise names.
+var equipmentSuffix = regexp.MustCompile(`^(.*?)\s*\(([^()]+)\)$`)
+
+type ImportService struct {
+	db    *sql.DB
+	cache cache.Cache
+}
+
+func NewImportService(db *sql.DB, cacheClient cache.Cache) *ImportService {
+	return &ImportService{
+		db:    db,
+		cache: cacheClient,
+	}
+}
+
+// Import parses a Strong or Hevy export and adds its workouts as completed
+// sessions. Workouts whose start time and name already exist are skipped, so
+// importing the same file twice is harmless. overrides maps foreign exercise
+// names to catalog definition IDs chosen by the user. With dryRun nothing is
+// written and the report shows what an import would do.
+func (s *ImportService) Import(ctx context.Context, userID int, file io.Reader, opts importer.Options, dryRun bool, overrides map[string]int) (*models.ImportReport, error) {
+	result, err := importer.Parse(file, opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid import file: %w", err)
+	}
+
+	report := &models.ImportReport{
+		Source:        result.Source,
+		DryRun:        dryRun,
+		WorkoutsFound: len(result.Workouts),
+		RowsSkipped:   result.RowsSkipped,
+		Mappings:      []models.ExerciseMapping{},
+		Errors:        result.Errors,
+	}
+
+	if dryRun {
+		if _, _, err := planImport(ctx, s.db, userID, result.Workouts, overrides, report, false); err != nil {
+			return nil, err
+		}
+		return report, nil
+	}
+
+	err = queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+
+		if err := workoutQueries.LockWorkoutHistory(ctx, userID); err != nil {
+			return err
+		}
+
+		workouts, definitions, err := planImport(ctx, tx, userID, result.Workouts, overrides, report, true)
+		if err != nil {
+			return err
+		}
+
+		return insertImportedWorkouts(ctx, workoutQueries, userID, workouts, definitions)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if report.WorkoutsImported > 0 {
+		s.invalidateStatsCaches(ctx, userID)
+	}
+
+	return report, nil
+}
+
+// planImport drops workouts that already exist and maps every remaining
+// exercise name onto a definition, filling in report. With create, unmatched
+// names become custom definitions; otherwise they are only reported.
+func planImport(ctx context.Context, db queries.Querier, userID int, parsed []importer.Workout, overrides map[string]int, report *models.ImportReport, create bool) ([]importer.Workout, map[string]int, error) {
+	workouts, err := dropExistingWorkouts(ctx, queries.NewWorkoutQueries(db), userID, parsed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	catalogQueries := queries.NewCatalogQueries(db)
+	definitions := map[string]int{}
+	mappingIndex := map[string]int{}
+
+	for _, workout := range workouts {
+		for _, exercise := range workout.Exercises {
+			report.SetsImported += len(exercise.Sets)
+
+			if i, ok := mappingIndex[exercise.Name]; ok {
+				report.Mappings[i].SetCount += len(exercise.Sets)
+				continue
+			}
+
+			var override *int
+			if id, ok := overrides[exercise.Name]; ok {
+				override = &id
+			}
+
+			mapping, err := mapExerciseName(ctx, catalogQueries, userID, exercise.Name, override, create)
+			if err != nil {
+				return nil, nil, err
+			}
+			mapping.SetCount = len(exercise.Sets)
+
+			if mapping.ExerciseDefinitionID != nil {
+				definitions[exercise.Name] = *mapping.ExerciseDefinitionID
+			}
+			mappingIndex[exercise.Name] = len(report.Mappings)
+			report.Mappings = append(report.Mappings, mapping)
+		}
+	}
+
+	report.WorkoutsImported = len(workouts)
+	report.WorkoutsSkipped = len(parsed) - len(workouts)
+
+	return workouts, definitions, nil
+}
+
+// dropExistingWorkouts removes workouts that match an existing one, or an
+// earlier one in the same file, by start time and name.
+func dropExistingWorkouts(ctx context.Context, workoutQueries *queries.WorkoutQueries, userID int, parsed []importer.Workout) ([]importer.Workout, error) {
+	if len(parsed) == 0 {
+		return parsed, nil
+	}
+
+	startedAts := make([]time.Time, len(parsed))
+	for i, workout := range parsed {
+		startedAts[i] = workout.StartedAt
+	}
+
+	existing, err := workoutQueries.FindWorkoutsByStart(ctx, userID, startedAts)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, workout := range existing {
+		seen[importKey(workout.StartedAt, workout.Name)] = true
+	}
+
+	workouts := []importer.Workout{}
+	for _, workout := range parsed {
+		key := importKey(workout.StartedAt, workout.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		workouts = append(workouts, workout)
+	}
+
+	return workouts, nil
+}
+
+func importKey(startedAt time.Time, name string) string {
+	return startedAt.UTC().Format(time.RFC3339Nano) + "|" + name
+}
+
+// mapExerciseName resolves a foreign exercise name. An override wins; then
+// the name or an alias; then the name with its equipment suffix folded in
+// ("Bench Press (Dumbbell)" as "Dumbbell Bench Press"), or dropped when the
+// definition uses that equipment. Anything else is new, with the closest
+// catalog names offered as suggestions.
+func mapExerciseName(ctx context.Context, catalogQueries *queries.CatalogQueries, userID int, name string, override *int, create bool) (models.ExerciseMapping, error) {
+	mapping := models.ExerciseMapping{SourceName: name}
+
+	if override != nil {
+		definition, err := resolveExercise(ctx, catalogQueries, userID, override, "")
+		if err != nil {
+			return mapping, fmt.Errorf("mapping for %q: %w", name, err)
+		}
+		return mappedTo(mapping, definition, models.MappingOverride), nil
+	}
+
+	definition, err := matchCatalogName(ctx, catalogQueries, userID, name)
+	if err != nil {
+		return mapping, err
+	}
+	if definition != nil {
+		return mappedTo(mapping, definition, models.MappingMatched), nil
+	}
+
+	mapping.Match = models.MappingNew
+	mapping.ExerciseName = name
+
+	base := name
+	if parts := equipmentSuffix.FindStringSubmatch(name); parts != nil {
+		base = parts[1]
+	}
+	suggestions, err := catalogQueries.SearchDefinitions(ctx, userID, base, maxImportSuggestions)
+	if err != nil {
+		return mapping, err
+	}
+	for _, suggestion := range suggestions {
+		mapping.Suggestions = append(mapping.Suggestions, models.ExerciseSuggestion{ID: suggestion.ID, Name: suggestion.Name})
+	}
+
+	if create {
+		definition, err := resolveExercise(ctx, catalogQueries, userID, nil, name)
+		if err != nil {
+			return mapping, err
+		}
+		mapping.ExerciseDefinitionID = &definition.ID
+	}
+
+	return mapping, nil
+}
+
+// matchCatalogName returns nil without an error when nothing matches.
+func matchCatalogName(ctx context.Context, catalogQueries *queries.CatalogQueries, userID int, name string) (*models.ExerciseDefinition, error) {
+	candidates := []string{name}
+	var equipment string
+	if parts := equipmentSuffix.FindStringSubmatch(name); parts != nil {
+		equipment = strings.ToLower(strings.TrimSpace(parts[2]))
+		candidates = append(candidates, parts[2]+" "+parts[1], parts[1])
+	}
+
+	for i, candidate := range candidates {
+		definition, err := catalogQueries.FindDefinitionByName(ctx, userID, candidate)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				continue
+			}
+			return nil, err
+		}
+
+		// The bare name only counts if the equipment agrees; "Curl (Cable)"
+		// is not the barbell curl.
+		if i == 2 && definition.Equipment != equipment && !(equipment == "bodyweight" && definition.IsBodyweight) {
+			continue
+		}
+
+		return definition, nil
+	}
+
+	return nil, nil
+}
+
+func mappedTo(mapping models.ExerciseMapping, definition *models.ExerciseDefinition, match string) models.ExerciseMapping {
+	id := definition.ID
+	mapping.ExerciseDefinitionID = &id
+	mapping.ExerciseName = definition.Name
+	mapping.Match = match
+	return mapping
+}
+
+// insertImportedWorkouts writes workouts in batches: one insert for the
+// workouts, one for their exercises and one for their sets per batch.
+func insertImportedWorkouts(ctx context.Context, workoutQueries *queries.WorkoutQueries, userID int, workouts []importer.Workout, definitions map[string]int) error {
+	for start := 0; start < len(workouts); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(workouts) {
+			end = len(workouts)
+		}
+		batch := workouts[start:end]
+
+		sessions := make([]models.WorkoutSession, len(batch))
+		for i, workout := range batch {
+			completedAt := workout.CompletedAt
+			sessions[i] = models.WorkoutSession{
+				Name:        workout.Name,
+				StartedAt:   workout.StartedAt,
+				CompletedAt: &completedAt,
+			}
+		}
+
+		workoutIDs, err := workoutQueries.CreateCompletedWorkouts(ctx, userID, sessions)
+		if err != nil {
+			return err
+		}
+
+		exercises := []models.Exercise{}
+		for i, workout := range batch {
+			for orderIndex, exercise := range workout.Exercises {
+				exercises = append(exercises, models.Exercise{
+					WorkoutSessionID:     workoutIDs[i],
+					ExerciseDefinitionID: definitions[exercise.Name],
+					Name:                 exercise.Name,
+					OrderIndex:           orderIndex,
+				})
+			}
+		}
+
+		exerciseIDs, err := workoutQueries.CreateExercises(ctx, exercises)
+		if err != nil {
+			return err
+		}
+
+		sets := []models.Set{}
+		next := 0
+		for _, workout := range batch {
+			for _, exercise := range workout.Exercises {
+				for setNumber, input := range exercise.Sets {
+					sets = append(sets, *newSet(exerciseIDs[next], setNumber+1, input))
+				}
+				next++
+			}
+		}
+
+		if err := workoutQueries.CreateSets(ctx, sets); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ImportService) invalidateStatsCaches(ctx context.Context, userID int) {
+	patterns := []string{
+		cache.GetUserPRsPattern(userID),
+		cache.GetWeeklySummaryPattern(userID),
+		cache.GetExerciseProgressPattern(userID),
+	}
+
+	for _, pattern := range patterns {
+		if err := s.cache.DeletePattern(ctx, pattern); err != nil {
+			fmt.Printf("Failed to invalidate caches: %v\n", err)
+		}
+	}
+}
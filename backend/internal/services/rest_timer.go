@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/cache"
+	"github.com/damion-14/cadence/backend/internal/database/queries"
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+// defaultRestSeconds is the timer length when neither the request nor the
+// exercise definition sets one.
+const defaultRestSeconds = 90
+
+// StartRestTimer starts the user's rest timer in their active workout,
+// replacing any timer already running. Without durationSeconds the rest
+// comes from the exercise's definition.
+func (s *WorkoutService) StartRestTimer(ctx context.Context, userID, workoutID int, exerciseID, durationSeconds *int) (*models.RestTimer, error) {
+	workout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workout.UserID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	if workout.Status != models.WorkoutStatusActive {
+		return nil, fmt.Errorf("workout is not active")
+	}
+
+	duration := defaultRestSeconds
+
+	if exerciseID != nil {
+		exercise := findExercise(workout.Exercises, *exerciseID)
+		if exercise == nil {
+			return nil, fmt.Errorf("exercise does not belong to this workout")
+		}
+
+		definition, err := queries.NewCatalogQueries(s.db).GetDefinitionByID(ctx, exercise.ExerciseDefinitionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if definition.DefaultRestSeconds != nil {
+			duration = *definition.DefaultRestSeconds
+		}
+	}
+
+	if durationSeconds != nil {
+		duration = *durationSeconds
+	}
+
+	timer := &models.RestTimer{
+		WorkoutSessionID: workoutID,
+		ExerciseID:       exerciseID,
+		StartedAt:        time.Now(),
+		DurationSeconds:  duration,
+	}
+
+	if err := s.saveRestTimer(ctx, userID, timer); err != nil {
+		return nil, err
+	}
+
+	s.publishWorkoutEvent(ctx, userID, models.WorkoutEventRestTimer, workoutID)
+
+	timer.SetRemaining(time.Now())
+	return timer, nil
+}
+
+// GetRestTimer returns the timer running in the workout, or nil.
+func (s *WorkoutService) GetRestTimer(ctx context.Context, userID, workoutID int) (*models.RestTimer, error) {
+	timer, err := s.loadRestTimer(ctx, userID, workoutID)
+	if err != nil || timer == nil {
+		return nil, err
+	}
+
+	timer.SetRemaining(time.Now())
+	return timer, nil
+}
+
+// ExtendRestTimer lengthens the running timer by seconds, or shortens it
+// for negative values, keeping the total within 0 and MaxRestSeconds.
+func (s *WorkoutService) ExtendRestTimer(ctx context.Context, userID, workoutID, seconds int) (*models.RestTimer, error) {
+	timer, err := s.loadRestTimer(ctx, userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if timer == nil {
+		return nil, fmt.Errorf("rest timer not found")
+	}
+
+	timer.DurationSeconds += seconds
+	if timer.DurationSeconds < 0 {
+		timer.DurationSeconds = 0
+	}
+	if timer.DurationSeconds > models.MaxRestSeconds {
+		timer.DurationSeconds = models.MaxRestSeconds
+	}
+
+	if err := s.saveRestTimer(ctx, userID, timer); err != nil {
+		return nil, err
+	}
+
+	s.publishWorkoutEvent(ctx, userID, models.WorkoutEventRestTimer, workoutID)
+
+	timer.SetRemaining(time.Now())
+	return timer, nil
+}
+
+// StopRestTimer discards the timer without recording any rest.
+func (s *WorkoutService) StopRestTimer(ctx context.Context, userID, workoutID int) error {
+	timer, err := s.loadRestTimer(ctx, userID, workoutID)
+	if err != nil {
+		return err
+	}
+
+	if timer == nil {
+		return fmt.Errorf("rest timer not found")
+	}
+
+	if err := s.cache.Delete(ctx, cache.GetRestTimerKey(userID)); err != nil {
+		return err
+	}
+
+	s.publishWorkoutEvent(ctx, userID, models.WorkoutEventRestTimer, workoutID)
+	return nil
+}
+
+// elapsedRest reports how long the user has rested since the timer in the
+// workout started, or nil without one. The next set logged takes this as its
+// rest_seconds.
+func (s *WorkoutService) elapsedRest(ctx context.Context, userID, workoutID int) *int {
+	timer, err := s.loadRestTimer(ctx, userID, workoutID)
+	if err != nil {
+		fmt.Printf("Failed to load rest timer: %v\n", err)
+		return nil
+	}
+
+	if timer == nil {
+		return nil
+	}
+
+	elapsed := int(time.Since(timer.StartedAt) / time.Second)
+	if elapsed > models.MaxRestSeconds {
+		elapsed = models.MaxRestSeconds
+	}
+	return &elapsed
+}
+
+// clearRestTimer drops the timer once its rest has been recorded.
+func (s *WorkoutService) clearRestTimer(ctx context.Context, userID, workoutID int) {
+	if err := s.cache.Delete(ctx, cache.GetRestTimerKey(userID)); err != nil {
+		fmt.Printf("Failed to clear rest timer: %v\n", err)
+		return
+	}
+
+	s.publishWorkoutEvent(ctx, userID, models.WorkoutEventRestTimer, workoutID)
+}
+
+// loadRestTimer returns nil when no timer is cached or the cached one
+// belongs to a different workout.
+func (s *WorkoutService) loadRestTimer(ctx context.Context, userID, workoutID int) (*models.RestTimer, error) {
+	data, err := s.cache.Get(ctx, cache.GetRestTimerKey(userID))
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var timer models.RestTimer
+	if err := json.Unmarshal([]byte(data), &timer); err != nil {
+		return nil, err
+	}
+
+	if timer.WorkoutSessionID != workoutID {
+		return nil, nil
+	}
+
+	return &timer, nil
+}
+
+func (s *WorkoutService) saveRestTimer(ctx context.Context, userID int, timer *models.RestTimer) error {
+	data, err := json.Marshal(timer)
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Set(ctx, cache.GetRestTimerKey(userID), data, cache.TTLRestTimer)
+}
+
+func findExercise(exercises []models.Exercise, exerciseID int) *models.Exercise {
+	for i := range exercises {
+		if exercises[i].ID == exerciseID {
+			return &exercises[i]
+		}
+	}
+	return nil
+}
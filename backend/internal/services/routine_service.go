@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/damion-14/cadence/backend/internal/database/queries"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+type RoutineService struct {
+	db             *sql.DB
+	routineQueries *queries.RoutineQueries
+	workoutQueries *queries.WorkoutQueries
+}
+
+func NewRoutineService(db *sql.DB) *RoutineService {
+	return &RoutineService{
+		db:             db,
+		routineQueries: queries.NewRoutineQueries(db),
+		workoutQueries: queries.NewWorkoutQueries(db),
+	}
+}
+
+func (s *RoutineService) ListRoutines(ctx context.Context, userID int) ([]models.Routine, error) {
+	return s.routineQueries.GetRoutinesByUserID(ctx, userID)
+}
+
+func (s *RoutineService) GetRoutine(ctx context.Context, userID, routineID int) (*models.Routine, error) {
+	routine, err := s.routineQueries.GetRoutineByID(ctx, routineID)
+	if err != nil {
+		return nil, err
+	}
+
+	if routine.UserID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	return routine, nil
+}
+
+func (s *RoutineService) CreateRoutine(ctx context.Context, userID int, name string, exercises []models.RoutineExerciseInput) (*models.Routine, error) {
+	var routine *models.Routine
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		routineQueries := queries.NewRoutineQueries(tx)
+
+		var err error
+		routine, err = routineQueries.CreateRoutine(ctx, userID, name)
+		if err != nil {
+			return err
+		}
+
+		routine.Exercises, err = createRoutineExercises(ctx, routineQueries, queries.NewCatalogQueries(tx), userID, routine.ID, exercises)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return routine, nil
+}
+
+// UpdateRoutine renames the routine and replaces its exercise list.
+func (s *RoutineService) UpdateRoutine(ctx context.Context, userID, routineID int, name string, exercises []models.RoutineExerciseInput) (*models.Routine, error) {
+	var routine *models.Routine
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		routineQueries := queries.NewRoutineQueries(tx)
+
+		existing, err := routineQueries.GetRoutineByID(ctx, routineID)
+		if err != nil {
+			return err
+		}
+
+		if existing.UserID != userID {
+			return fmt.Errorf("unauthorized")
+		}
+
+		routine, err = routineQueries.UpdateRoutineName(ctx, routineID, name)
+		if err != nil {
+			return err
+		}
+
+		if err := routineQueries.DeleteRoutineExercises(ctx, routineID); err != nil {
+			return err
+		}
+
+		routine.Exercises, err = createRoutineExercises(ctx, routineQueries, queries.NewCatalogQueries(tx), userID, routine.ID, exercises)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return routine, nil
+}
+
+func (s *RoutineService) DeleteRoutine(ctx context.Context, userID, routineID int) error {
+	if _, err := s.GetRoutine(ctx, userID, routineID); err != nil {
+		return err
+	}
+
+	return s.routineQueries.DeleteRoutine(ctx, routineID)
+}
+
+// CreateRoutineFromWorkout turns a completed workout into a template. Each
+// exercise targets its number of working sets at the reps and weight of its
+// top set; warm-ups are left out.
+func (s *RoutineService) CreateRoutineFromWorkout(ctx context.Context, userID, workoutID int, name string) (*models.Routine, error) {
+	workout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workout.UserID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	if workout.Status != "completed" {
+		return nil, fmt.Errorf("workout is not completed")
+	}
+
+	if name == "" {
+		name = workout.Name
+	}
+	if name == "" {
+		return nil, fmt.Errorf("routine name is required")
+	}
+
+	exercises := []models.RoutineExerciseInput{}
+	for _, exercise := range workout.Exercises {
+		working := []models.Set{}
+		for _, set := range exercise.Sets {
+			if set.SetType != models.SetTypeWarmup {
+				working = append(working, set)
+			}
+		}
+
+		if len(working) == 0 {
+			continue
+		}
+
+		top := working[0]
+		for _, set := range working[1:] {
+			if weightOf(set) > weightOf(top) || (weightOf(set) == weightOf(top) && set.Reps > top.Reps) {
+				top = set
+			}
+		}
+
+		definitionID := exercise.ExerciseDefinitionID
+		exercises = append(exercises, models.RoutineExerciseInput{
+			Name:                 exercise.Name,
+			ExerciseDefinitionID: &definitionID,
+			TargetSets:           len(working),
+			TargetReps:           top.Reps,
+			TargetWeight:         top.Weight,
+			WeightUnit:           top.WeightUnit,
+			IsBodyweight:         top.IsBodyweight,
+		})
+	}
+
+	if len(exercises) == 0 {
+		return nil, fmt.Errorf("workout has no exercises")
+	}
+
+	return s.CreateRoutine(ctx, userID, name, exercises)
+}
+
+func createRoutineExercises(ctx context.Context, routineQueries *queries.RoutineQueries, catalogQueries *queries.CatalogQueries, userID, routineID int, inputs []models.RoutineExerciseInput) ([]models.RoutineExercise, error) {
+	exercises := []models.RoutineExercise{}
+	for i, input := range inputs {
+		definition, err := resolveExercise(ctx, catalogQueries, userID, input.ExerciseDefinitionID, input.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		input.ExerciseDefinitionID = &definition.ID
+		if input.Name == "" {
+			input.Name = definition.Name
+		}
+		if input.WeightUnit == "" {
+			input.WeightUnit = units.DefaultUnit
+		}
+
+		exercise, err := routineQueries.CreateRoutineExercise(ctx, routineID, i, input)
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, *exercise)
+	}
+
+	return exercises, nil
+}
+
+// weightOf returns the set's weight in kilograms so sets logged in
+// different units compare correctly.
+func weightOf(set models.Set) float64 {
+	if set.Weight == nil {
+		return 0
+	}
+	return units.Convert(*set.Weight, set.WeightUnit, units.Kilograms)
+}
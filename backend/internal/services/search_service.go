@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/damion-14/cadence/backend/internal/database/queries"
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+type SearchService struct {
+	searchQueries *queries.SearchQueries
+}
+
+func NewSearchService(db *sql.DB) *SearchService {
+	return &SearchService{
+		searchQueries: queries.NewSearchQueries(db),
+	}
+}
+
+func (s *SearchService) Search(ctx context.Context, userID int, search string, limit, offset int) ([]models.SearchHit, error) {
+	return s.searchQueries.Search(ctx, userID, search, limit, offset)
+}
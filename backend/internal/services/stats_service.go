@@ -16,18 +16,20 @@ import (
 
 type StatsService struct {
 	statsQueries *queries.StatsQueries
-	cache        *cache.Cache
+	catalog      *CatalogService
+	cache        cache.Cache
 }
 
-func NewStatsService(db *sql.DB, cacheClient *cache.Cache) *StatsService {
+func NewStatsService(db *sql.DB, cacheClient cache.Cache) *StatsService {
 	return &StatsService{
 		statsQueries: queries.NewStatsQueries(db),
+		catalog:      NewCatalogService(db),
 		cache:        cacheClient,
 	}
 }
 
-func (s *StatsService) GetPersonalRecords(ctx context.Context, userID int) ([]models.PersonalRecord, error) {
-	cacheKey := cache.GetUserPRsKey(userID)
+func (s *StatsService) GetPersonalRecords(ctx context.Context, userID int, formula string) ([]models.PersonalRecord, error) {
+	cacheKey := cache.GetUserPRsKey(userID, formula)
 
 	cachedData, err := s.cache.Get(ctx, cacheKey)
 	if err == nil {
@@ -37,7 +39,7 @@ func (s *StatsService) GetPersonalRecords(ctx context.Context, userID int) ([]mo
 		}
 	}
 
-	prs, err := s.statsQueries.GetPersonalRecords(ctx, userID)
+	prs, err := s.statsQueries.GetPersonalRecords(ctx, userID, formula)
 	if err != nil {
 		return nil, err
 	}
@@ -49,14 +51,137 @@ func (s *StatsService) GetPersonalRecords(ctx context.Context, userID int) ([]mo
 	return prs, nil
 }
 
-func (s *StatsService) GetWorkoutHistory(ctx context.Context, userID int, limit, offset int) ([]models.WorkoutSummary, int, error) {
-	return s.statsQueries.GetWorkoutHistory(ctx, userID, limit, offset)
+func (s *StatsService) GetPRTimeline(ctx context.Context, userID int, definitionID *int, limit, offset int) ([]models.PREvent, int, error) {
+	return s.statsQueries.GetPRTimeline(ctx, userID, definitionID, limit, offset)
 }
 
-func (s *StatsService) GetWeeklySummary(ctx context.Context, userID int, weekStr string) (*models.WeeklySummary, error) {
+// recordPREvents compares a just-completed workout against the records that
+// stood before it and stores an event for every record it beat. Exercises or
+// rep counts with no earlier record have nothing to beat and are skipped.
+func recordPREvents(ctx context.Context, statsQueries *queries.StatsQueries, userID, workoutID int) ([]models.PREvent, error) {
+	formula := models.DefaultE1RMFormula
+
+	prior, err := statsQueries.GetRecordsBeforeWorkout(ctx, userID, workoutID, formula)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := statsQueries.GetWorkoutRecords(ctx, userID, workoutID, formula)
+	if err != nil {
+		return nil, err
+	}
+
+	events := detectPREvents(prior, current)
+	for i := range events {
+		if err := statsQueries.CreatePREvent(ctx, userID, &events[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
+
+func detectPREvents(prior, current []models.PersonalRecord) []models.PREvent {
+	priorByExercise := map[int]models.PersonalRecord{}
+	for _, pr := range prior {
+		priorByExercise[pr.ExerciseDefinitionID] = pr
+	}
+
+	events := []models.PREvent{}
+	for _, pr := range current {
+		before, ok := priorByExercise[pr.ExerciseDefinitionID]
+		if !ok {
+			continue
+		}
+
+		pairs := [][2]*models.Record{
+			{before.HeaviestSingle, pr.HeaviestSingle},
+			{before.BestE1RM, pr.BestE1RM},
+			{before.MostReps, pr.MostReps},
+			{before.BestSessionVolume, pr.BestSessionVolume},
+		}
+		for i := range pr.RepMaxes {
+			pairs = append(pairs, [2]*models.Record{findRepMax(before.RepMaxes, *pr.RepMaxes[i].RepCount), &pr.RepMaxes[i]})
+		}
+
+		for _, pair := range pairs {
+			old, record := pair[0], pair[1]
+			if old == nil || record == nil || record.Value <= old.Value {
+				continue
+			}
+
+			event := models.PREvent{
+				WorkoutSessionID:     record.WorkoutID,
+				ExerciseDefinitionID: pr.ExerciseDefinitionID,
+				ExerciseName:         pr.ExerciseName,
+				SetID:                record.SetID,
+				Category:             record.Category,
+				RepCount:             record.RepCount,
+				OldValue:             old.Value,
+				NewValue:             record.Value,
+				AchievedAt:           record.AchievedAt,
+			}
+			switch record.Category {
+			case models.PRCategoryE1RM:
+				formula := pr.Formula
+				event.Formula = &formula
+			case models.PRCategoryHeaviestSingle, models.PRCategoryRepMax:
+				event.OldWeight, event.OldWeightUnit = old.Weight, &old.WeightUnit
+				event.NewWeight, event.NewWeightUnit = record.Weight, &record.WeightUnit
+			}
+			events = append(events, event)
+		}
+	}
+
+	return events
+}
+
+func findRepMax(records []models.Record, repCount int) *models.Record {
+	for i := range records {
+		if records[i].RepCount != nil && *records[i].RepCount == repCount {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+// GetWorkoutHistory returns a page of up to limit workouts after cursor and
+// the cursor for the next page, or nil on the last one. exerciseName, when
+// set, is resolved like progress names and narrows the filter to workouts
+// containing that exercise.
+func (s *StatsService) GetWorkoutHistory(ctx context.Context, userID int, filter models.HistoryFilter, exerciseName string, cursor *models.HistoryCursor, limit int) ([]models.WorkoutSummary, *models.HistoryCursor, error) {
+	if exerciseName != "" {
+		definition, err := s.catalog.FindDefinition(ctx, userID, exerciseName)
+		if err != nil {
+			if strings.Contains(err.Error(), "not found") {
+				return []models.WorkoutSummary{}, nil, nil
+			}
+			return nil, nil, err
+		}
+		filter.ExerciseDefinitionID = &definition.ID
+	}
+
+	// One extra row says whether another page follows.
+	workouts, err := s.statsQueries.GetWorkoutHistory(ctx, userID, filter, cursor, limit+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(workouts) <= limit {
+		return workouts, nil, nil
+	}
+
+	workouts = workouts[:limit]
+	last := workouts[limit-1]
+	return workouts, &models.HistoryCursor{CompletedAt: last.CompletedAt, ID: last.ID}, nil
+}
+
+// GetWeeklySummary takes an ISO week (YYYY-WNN, ISO week-numbering year) and
+// defaults to the current week in loc.
+func (s *StatsService) GetWeeklySummary(ctx context.Context, userID int, weekStr string, loc *time.Location) (*models.WeeklySummary, error) {
 	var year, week int
 	if weekStr == "" {
-		year, week = time.Now().ISOWeek()
+		year, week = time.Now().In(loc).ISOWeek()
 	} else {
 		parts := strings.Split(weekStr, "-W")
 		if len(parts) != 2 {
@@ -68,12 +193,12 @@ func (s *StatsService) GetWeeklySummary(ctx context.Context, userID int, weekStr
 			return nil, fmt.Errorf("invalid year in week format")
 		}
 		week, err = strconv.Atoi(parts[1])
-		if err != nil {
+		if err != nil || week < 1 || week > isoWeeksIn(year) {
 			return nil, fmt.Errorf("invalid week number in week format")
 		}
 	}
 
-	cacheKey := cache.GetWeeklySummaryKey(userID, fmt.Sprintf("%d-W%02d", year, week))
+	cacheKey := cache.GetWeeklySummaryKey(userID, loc.String(), fmt.Sprintf("%d-W%02d", year, week))
 
 	cachedData, err := s.cache.Get(ctx, cacheKey)
 	if err == nil {
@@ -83,7 +208,7 @@ func (s *StatsService) GetWeeklySummary(ctx context.Context, userID int, weekStr
 		}
 	}
 
-	summary, err := s.statsQueries.GetWeeklySummary(ctx, userID, year, week)
+	summary, err := s.statsQueries.GetWeeklySummary(ctx, userID, year, week, loc)
 	if err != nil {
 		return nil, err
 	}
@@ -95,35 +220,68 @@ func (s *StatsService) GetWeeklySummary(ctx context.Context, userID int, weekStr
 	return summary, nil
 }
 
-func (s *StatsService) GetExerciseProgress(ctx context.Context, userID int, exerciseName string, period string) ([]models.ProgressDataPoint, error) {
-	days := 30
-	if period != "" {
-		if strings.HasSuffix(period, "d") {
-			d, err := strconv.Atoi(strings.TrimSuffix(period, "d"))
-			if err == nil && d > 0 && d <= 365 {
-				days = d
-			}
-		}
+// isoWeeksIn returns 52 or 53; December 28 always falls in the last ISO week.
+func isoWeeksIn(year int) int {
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// GetExerciseProgress accepts any name or alias of an exercise; all spellings
+// that resolve to the same catalog definition share one progress series.
+func (s *StatsService) GetExerciseProgress(ctx context.Context, userID int, exerciseName string, period string, formula string, loc *time.Location) (*models.ExerciseDefinition, []models.ProgressDataPoint, error) {
+	days := periodDays(period)
+
+	definition, err := s.catalog.FindDefinition(ctx, userID, exerciseName)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	cacheKey := cache.GetExerciseProgressKey(userID, exerciseName)
+	cacheKey := cache.GetExerciseProgressKey(userID, loc.String(), definition.ID, days, formula)
 
 	cachedData, err := s.cache.Get(ctx, cacheKey)
 	if err == nil {
 		var dataPoints []models.ProgressDataPoint
 		if err := json.Unmarshal([]byte(cachedData), &dataPoints); err == nil {
-			return dataPoints, nil
+			return definition, dataPoints, nil
 		}
 	}
 
-	dataPoints, err := s.statsQueries.GetExerciseProgress(ctx, userID, exerciseName, days)
+	dataPoints, err := s.statsQueries.GetExerciseProgress(ctx, userID, definition.ID, days, formula, loc)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if data, err := json.Marshal(dataPoints); err == nil {
 		s.cache.Set(ctx, cacheKey, data, cache.TTLExerciseProgress)
 	}
 
-	return dataPoints, nil
+	return definition, dataPoints, nil
+}
+
+// GetAverageRest reports the average rest recorded before working sets of
+// each exercise over period, which takes the same "30d" form as progress.
+func (s *StatsService) GetAverageRest(ctx context.Context, userID int, period string) (int, []models.ExerciseRest, error) {
+	days := periodDays(period)
+
+	exercises, err := s.statsQueries.GetAverageRest(ctx, userID, days)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return days, exercises, nil
+}
+
+// periodDays parses a period such as "90d", falling back to 30 days for
+// anything missing or outside 1 to 365 days.
+func periodDays(period string) int {
+	days := 30
+	if period != "" {
+		if strings.HasSuffix(period, "d") {
+			d, err := strconv.Atoi(strings.TrimSuffix(period, "d"))
+			if err == nil && d > 0 && d <= 365 {
+				days = d
+			}
+		}
+	}
+	return days
 }
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+func TestISOWeeksIn(t *testing.T) {
+	tests := []struct {
+		year int
+		want int
+	}{
+		{2015, 53},
+		{2019, 52},
+		{2020, 53},
+		{2021, 52},
+		{2024, 52},
+		{2026, 53},
+	}
+
+	for _, tt := range tests {
+		if got := isoWeeksIn(tt.year); got != tt.want {
+			t.Errorf("isoWeeksIn(%d) = %d, want %d", tt.year, got, tt.want)
+		}
+	}
+}
+
+func record(category string, value float64) *models.Record {
+	return &models.Record{Category: category, Value: value, WorkoutID: 7}
+}
+
+func repMax(reps int, value float64) models.Record {
+	return models.Record{Category: models.PRCategoryRepMax, RepCount: &reps, Value: value, WorkoutID: 7}
+}
+
+// describePREvents flattens events into "exercise category[reps] old->new"
+// so cases can list what they expect compactly.
+func describePREvents(events []models.PREvent) []string {
+	described := []string{}
+	for _, event := range events {
+		category := event.Category
+		if event.RepCount != nil {
+			category = fmt.Sprintf("%s[%d]", category, *event.RepCount)
+		}
+		if event.Formula != nil {
+			category = fmt.Sprintf("%s(%s)", category, *event.Formula)
+		}
+		described = append(described, fmt.Sprintf("%d %s %g->%g", event.ExerciseDefinitionID, category, event.OldValue, event.NewValue))
+	}
+	return described
+}
+
+func TestDetectPREvents(t *testing.T) {
+	bench := func(records ...func(*models.PersonalRecord)) models.PersonalRecord {
+		pr := models.PersonalRecord{ExerciseDefinitionID: 1, ExerciseName: "Bench Press", Formula: models.E1RMEpley}
+		for _, set := range records {
+			set(&pr)
+		}
+		return pr
+	}
+	heaviest := func(value float64) func(*models.PersonalRecord) {
+		return func(pr *models.PersonalRecord) { pr.HeaviestSingle = record(models.PRCategoryHeaviestSingle, value) }
+	}
+	e1rm := func(value float64) func(*models.PersonalRecord) {
+		return func(pr *models.PersonalRecord) { pr.BestE1RM = record(models.PRCategoryE1RM, value) }
+	}
+	mostReps := func(value float64) func(*models.PersonalRecord) {
+		return func(pr *models.PersonalRecord) { pr.MostReps = record(models.PRCategoryMostReps, value) }
+	}
+	repMaxes := func(records ...models.Record) func(*models.PersonalRecord) {
+		return func(pr *models.PersonalRecord) { pr.RepMaxes = records }
+	}
+
+	tests := []struct {
+		name    string
+		prior   []models.PersonalRecord
+		current []models.PersonalRecord
+		want    []string
+	}{
+		{
+			name:    "first time an exercise is logged",
+			prior:   nil,
+			current: []models.PersonalRecord{bench(heaviest(100))},
+			want:    []string{},
+		},
+		{
+			name:    "heavier single",
+			prior:   []models.PersonalRecord{bench(heaviest(100))},
+			current: []models.PersonalRecord{bench(heaviest(102.5))},
+			want:    []string{"1 heaviest_single 100->102.5"},
+		},
+		{
+			name:    "tying a record is not a PR",
+			prior:   []models.PersonalRecord{bench(heaviest(100), mostReps(12))},
+			current: []models.PersonalRecord{bench(heaviest(100), mostReps(10))},
+			want:    []string{},
+		},
+		{
+			name:    "e1rm events carry the formula",
+			prior:   []models.PersonalRecord{bench(e1rm(110))},
+			current: []models.PersonalRecord{bench(e1rm(115))},
+			want:    []string{"1 e1rm(epley) 110->115"},
+		},
+		{
+			name:    "category with no earlier record",
+			prior:   []models.PersonalRecord{bench(heaviest(100))},
+			current: []models.PersonalRecord{bench(heaviest(90), mostReps(15))},
+			want:    []string{},
+		},
+		{
+			name:    "rep maxes compare at the same rep count",
+			prior:   []models.PersonalRecord{bench(repMaxes(repMax(3, 90), repMax(5, 80)))},
+			current: []models.PersonalRecord{bench(repMaxes(repMax(3, 85), repMax(5, 82.5), repMax(8, 70)))},
+			want:    []string{"1 rep_max[5] 80->82.5"},
+		},
+		{
+			name:  "several records in one workout",
+			prior: []models.PersonalRecord{bench(heaviest(100), e1rm(110), mostReps(10))},
+			current: []models.PersonalRecord{
+				bench(heaviest(105), e1rm(112), mostReps(11)),
+				{ExerciseDefinitionID: 2, ExerciseName: "Squat", HeaviestSingle: record(models.PRCategoryHeaviestSingle, 140)},
+			},
+			want: []string{
+				"1 heaviest_single 100->105",
+				"1 e1rm(epley) 110->112",
+				"1 most_reps 10->11",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := describePREvents(detectPREvents(tt.prior, tt.current))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("detectPREvents = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/damion-14/cadence/backend/internal/cache"
+	"github.com/damion-14/cadence/backend/internal/database/queries"
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+type UserService struct {
+	userQueries *queries.UserQueries
+	cache       cache.Cache
+}
+
+func NewUserService(db *sql.DB, cacheClient cache.Cache) *UserService {
+	return &UserService{
+		userQueries: queries.NewUserQueries(db),
+		cache:       cacheClient,
+	}
+}
+
+func (s *UserService) GetUser(ctx context.Context, userID int) (*models.User, error) {
+	return s.userQueries.GetUserByID(ctx, userID)
+}
+
+func (s *UserService) GetPreferences(ctx context.Context, userID int) (*models.UserPreferences, error) {
+	cacheKey := cache.GetUserPreferencesKey(userID)
+
+	cachedData, err := s.cache.Get(ctx, cacheKey)
+	if err == nil {
+		var prefs models.UserPreferences
+		if err := json.Unmarshal([]byte(cachedData), &prefs); err == nil {
+			return &prefs, nil
+		}
+	}
+
+	user, err := s.userQueries.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := preferencesOf(user)
+	if data, err := json.Marshal(prefs); err == nil {
+		s.cache.Set(ctx, cacheKey, data, cache.TTLUserPreferences)
+	}
+
+	return prefs, nil
+}
+
+func (s *UserService) UpdatePreferences(ctx context.Context, userID int, req models.UpdatePreferencesRequest) (*models.User, error) {
+	user, err := s.userQueries.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	preferredUnit := user.PreferredUnit
+	if req.PreferredUnit != nil {
+		preferredUnit = *req.PreferredUnit
+	}
+
+	timezone := user.Timezone
+	if req.Timezone != nil {
+		timezone = *req.Timezone
+	}
+
+	user, err = s.userQueries.UpdatePreferences(ctx, userID, preferredUnit, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.Delete(ctx, cache.GetUserPreferencesKey(userID)); err != nil {
+		fmt.Printf("Failed to invalidate preferences cache: %v\n", err)
+	}
+
+	return user, nil
+}
+
+func preferencesOf(user *models.User) *models.UserPreferences {
+	return &models.UserPreferences{
+		PreferredUnit: user.PreferredUnit,
+		Timezone:      user.Timezone,
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/damion-14/cadence/backend/internal/cache"
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+// SubscribeWorkoutEvents delivers changes to the user's unfinished workout
+// and rest timer made through any API instance until ctx is cancelled.
+func (s *WorkoutService) SubscribeWorkoutEvents(ctx context.Context, userID int) (<-chan models.WorkoutEvent, error) {
+	sub, err := s.cache.Subscribe(ctx, cache.GetWorkoutEventsChannel(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan models.WorkoutEvent)
+	go func() {
+		defer close(events)
+		defer sub.Close()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case payload, ok := <-sub.Messages():
+				if !ok {
+					return
+				}
+
+				var event models.WorkoutEvent
+				if err := json.Unmarshal([]byte(payload), &event); err != nil {
+					fmt.Printf("Failed to decode workout event: %v\n", err)
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func (s *WorkoutService) publishWorkoutEvent(ctx context.Context, userID int, eventType string, workoutID int) {
+	data, err := json.Marshal(models.WorkoutEvent{
+		Type:             eventType,
+		WorkoutSessionID: workoutID,
+	})
+	if err != nil {
+		return
+	}
+
+	if err := s.cache.Publish(ctx, cache.GetWorkoutEventsChannel(userID), data); err != nil {
+		fmt.Printf("Failed to publish workout event: %v\n", err)
+	}
+}
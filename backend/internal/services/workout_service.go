@@ -5,37 +5,336 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/damion-14/cadence/backend/internal/cache"
+	"github.com/damion-14/cadence/backend/internal/config"
 	"github.com/damion-14/cadence/backend/internal/database/queries"
 	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/units"
 )
 
+// WorkoutConflictError reports that a workout clashes with one the user
+// already has.
+type WorkoutConflictError struct {
+	WorkoutID int
+	Message   string
+}
+
+func (e *WorkoutConflictError) Error() string {
+	return e.Message
+}
+
+// VersionConflictError reports that a workout or exercise changed since the
+// client read the version it sent in If-Match. Workout or Exercise holds the
+// current state so the client can reconcile.
+type VersionConflictError struct {
+	Resource        string
+	ID              int
+	ExpectedVersion int
+	CurrentVersion  int
+	Workout         *models.WorkoutSession
+	Exercise        *models.Exercise
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s %d is at version %d, not %d", e.Resource, e.ID, e.CurrentVersion, e.ExpectedVersion)
+}
+
 type WorkoutService struct {
+	db             *sql.DB
 	workoutQueries *queries.WorkoutQueries
-	cache          *cache.Cache
+	cache          cache.Cache
+	config         config.WorkoutConfig
 }
 
-func NewWorkoutService(db *sql.DB, cacheClient *cache.Cache) *WorkoutService {
+func NewWorkoutService(db *sql.DB, cacheClient cache.Cache, workoutConfig config.WorkoutConfig) *WorkoutService {
 	return &WorkoutService{
+		db:             db,
 		workoutQueries: queries.NewWorkoutQueries(db),
 		cache:          cacheClient,
+		config:         workoutConfig,
 	}
 }
 
-func (s *WorkoutService) CreateWorkout(ctx context.Context, userID int, name string) (*models.WorkoutSession, error) {
-	workout, err := s.workoutQueries.CreateWorkout(ctx, userID, name)
+// CreateWorkout starts a workout. A user has at most one unfinished workout;
+// if one exists, replace says whether to complete or abandon it first, and
+// without it a WorkoutConflictError names the existing workout. Completing it
+// returns the PR events it set, as CompleteWorkout does.
+func (s *WorkoutService) CreateWorkout(ctx context.Context, userID int, name string, routineID *int, replace string) (*models.WorkoutSession, []models.PREvent, error) {
+	var workout *models.WorkoutSession
+	var completedID int
+	var events []models.PREvent
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+
+		if err := workoutQueries.LockWorkoutHistory(ctx, userID); err != nil {
+			return err
+		}
+
+		var err error
+		completedID, events, err = endUnfinishedWorkout(ctx, tx, userID, replace)
+		if err != nil {
+			return err
+		}
+
+		if routineID == nil {
+			workout, err = workoutQueries.CreateWorkout(ctx, userID, name)
+			return err
+		}
+
+		routine, err := queries.NewRoutineQueries(tx).GetRoutineByID(ctx, *routineID)
+		if err != nil {
+			return err
+		}
+
+		if routine.UserID != userID {
+			return fmt.Errorf("unauthorized")
+		}
+
+		if name == "" {
+			name = routine.Name
+		}
+
+		workout, err = workoutQueries.CreateWorkout(ctx, userID, name)
+		if err != nil {
+			return err
+		}
+
+		workout.Exercises, err = createExercisesFromRoutine(ctx, workoutQueries, workout.ID, routine)
+		return err
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if completedID != 0 {
+		if _, err := s.afterCompletion(ctx, userID, completedID); err != nil {
+			fmt.Printf("Failed to load completed workout: %v\n", err)
+		}
 	}
 
 	if err := s.cacheActiveWorkout(ctx, userID, workout); err != nil {
 		fmt.Printf("Failed to cache active workout: %v\n", err)
 	}
 
+	s.publishWorkoutEvent(ctx, userID, models.WorkoutEventUpdated, workout.ID)
+
+	return workout, events, nil
+}
+
+// endUnfinishedWorkout makes room for a new workout by completing or
+// abandoning the user's unfinished one, as replace says. A completed
+// workout's ID and PR events are returned for the caller to finish with
+// afterCompletion once the transaction commits.
+func endUnfinishedWorkout(ctx context.Context, tx *sql.Tx, userID int, replace string) (int, []models.PREvent, error) {
+	workoutQueries := queries.NewWorkoutQueries(tx)
+
+	existing, err := workoutQueries.GetActiveWorkout(ctx, userID)
+	if err != nil || existing == nil {
+		return 0, nil, err
+	}
+
+	switch replace {
+	case models.ReplaceComplete:
+		events, err := completeWorkout(ctx, tx, userID, existing.ID)
+		if err != nil {
+			return 0, nil, err
+		}
+		return existing.ID, events, nil
+	case models.ReplaceAbandon:
+		if _, err := lockForTransition(ctx, workoutQueries, userID, existing.ID, models.WorkoutStatusAbandoned); err != nil {
+			return 0, nil, err
+		}
+		return 0, nil, workoutQueries.AbandonWorkout(ctx, existing.ID)
+	}
+
+	return 0, nil, &WorkoutConflictError{
+		WorkoutID: existing.ID,
+		Message:   "an unfinished workout already exists",
+	}
+}
+
+// LogWorkout records a finished workout after the fact. It is stored as
+// completed from the start, so it never becomes the active workout and does
+// not produce PR events.
+func (s *WorkoutService) LogWorkout(ctx context.Context, userID int, req models.LogWorkoutRequest) (*models.WorkoutSession, error) {
+	if err := checkWorkoutTimes(*req.StartedAt, *req.CompletedAt); err != nil {
+		return nil, err
+	}
+
+	var workoutID int
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+		catalogQueries := queries.NewCatalogQueries(tx)
+
+		if err := workoutQueries.LockWorkoutHistory(ctx, userID); err != nil {
+			return err
+		}
+
+		if err := checkOverlap(ctx, workoutQueries, userID, 0, *req.StartedAt, *req.CompletedAt); err != nil {
+			return err
+		}
+
+		workoutIDs, err := workoutQueries.CreateCompletedWorkouts(ctx, userID, []models.WorkoutSession{{
+			Name:        req.Name,
+			StartedAt:   *req.StartedAt,
+			CompletedAt: req.CompletedAt,
+			Notes:       notesValue(req.Notes),
+		}})
+		if err != nil {
+			return err
+		}
+		workoutID = workoutIDs[0]
+
+		exercises := make([]models.Exercise, len(req.Exercises))
+		for i, input := range req.Exercises {
+			definition, err := resolveExercise(ctx, catalogQueries, userID, input.ExerciseDefinitionID, input.Name)
+			if err != nil {
+				return err
+			}
+
+			name := input.Name
+			if name == "" {
+				name = definition.Name
+			}
+
+			exercises[i] = models.Exercise{
+				WorkoutSessionID:     workoutID,
+				ExerciseDefinitionID: definition.ID,
+				Name:                 name,
+				OrderIndex:           i,
+				Notes:                notesValue(input.Notes),
+			}
+		}
+
+		exerciseIDs, err := workoutQueries.CreateExercises(ctx, exercises)
+		if err != nil {
+			return err
+		}
+
+		sets := []models.Set{}
+		for i, input := range req.Exercises {
+			for n, setInput := range input.Sets {
+				set := newSet(exerciseIDs[i], n+1, setInput)
+				set.IsCompleted = true
+				sets = append(sets, *set)
+			}
+		}
+
+		return workoutQueries.CreateSets(ctx, sets)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateWorkoutStats(ctx, userID, workout)
+
 	return workout, nil
 }
 
+// UpdateWorkout renames a workout, edits its notes or moves its start and
+// end times. Moved times must still describe a past workout that overlaps no
+// other.
+func (s *WorkoutService) UpdateWorkout(ctx context.Context, userID, workoutID int, req models.UpdateWorkoutRequest, ifMatch *int) (*models.WorkoutSession, error) {
+	var edit *workoutEdit
+	movesTimes := req.StartedAt != nil || req.CompletedAt != nil
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+
+		if movesTimes {
+			if err := workoutQueries.LockWorkoutHistory(ctx, userID); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		edit, err = lockEditableWorkout(ctx, workoutQueries, userID, workoutID)
+		if err != nil {
+			return err
+		}
+		workout := edit.workout
+
+		if err := checkWorkoutVersion(ctx, workoutQueries, workout, ifMatch); err != nil {
+			return err
+		}
+
+		if req.CompletedAt != nil && workout.Status != models.WorkoutStatusCompleted {
+			return fmt.Errorf("invalid workout times: only completed workouts have completed_at")
+		}
+
+		name := workout.Name
+		if req.Name != nil {
+			name = *req.Name
+		}
+
+		startedAt := workout.StartedAt
+		if req.StartedAt != nil {
+			startedAt = *req.StartedAt
+		}
+
+		completedAt := workout.CompletedAt
+		if req.CompletedAt != nil {
+			completedAt = req.CompletedAt
+		}
+
+		notes := workout.Notes
+		if req.Notes != nil {
+			notes = notesValue(req.Notes)
+		}
+
+		if movesTimes {
+			end := time.Now()
+			if completedAt != nil {
+				end = *completedAt
+			}
+
+			if err := checkWorkoutTimes(startedAt, end); err != nil {
+				return err
+			}
+
+			if err := checkOverlap(ctx, workoutQueries, userID, workoutID, startedAt, end); err != nil {
+				return err
+			}
+		}
+
+		if err := workoutQueries.UpdateWorkout(ctx, workoutID, name, startedAt, completedAt, notes); err != nil {
+			return err
+		}
+
+		return edit.record(ctx, workoutQueries, userID, models.RevisionUpdateWorkout)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.afterEdit(ctx, userID, edit)
+
+	return s.workoutQueries.GetWorkoutByID(ctx, workoutID)
+}
+
+// GetRevisions lists the edits made to a completed workout, newest first.
+func (s *WorkoutService) GetRevisions(ctx context.Context, userID, workoutID int) ([]models.WorkoutRevision, error) {
+	workout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workout.UserID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	return s.workoutQueries.ListRevisions(ctx, workoutID)
+}
+
 func (s *WorkoutService) GetWorkout(ctx context.Context, workoutID int) (*models.WorkoutSession, error) {
 	return s.workoutQueries.GetWorkoutByID(ctx, workoutID)
 }
@@ -47,6 +346,7 @@ func (s *WorkoutService) GetActiveWorkout(ctx context.Context, userID int) (*mod
 	if err == nil {
 		var workout models.WorkoutSession
 		if err := json.Unmarshal([]byte(cachedData), &workout); err == nil {
+			workout.SetActiveSeconds(time.Now())
 			return &workout, nil
 		}
 	}
@@ -65,191 +365,1023 @@ func (s *WorkoutService) GetActiveWorkout(ctx context.Context, userID int) (*mod
 	return workout, nil
 }
 
-func (s *WorkoutService) CompleteWorkout(ctx context.Context, userID, workoutID int) (*models.WorkoutSession, error) {
-	if err := s.workoutQueries.CompleteWorkout(ctx, workoutID); err != nil {
-		return nil, err
+// CompleteWorkout marks the workout completed and records any PRs it set in
+// the same transaction. Caches are only touched after the commit so a failed
+// completion never evicts state that is still current.
+func (s *WorkoutService) CompleteWorkout(ctx context.Context, userID, workoutID int) (*models.WorkoutSession, []models.PREvent, error) {
+	var events []models.PREvent
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		var err error
+		events, err = completeWorkout(ctx, tx, userID, workoutID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	if err := s.invalidateCachesOnComplete(ctx, userID); err != nil {
-		fmt.Printf("Failed to invalidate caches: %v\n", err)
+	workout, err := s.afterCompletion(ctx, userID, workoutID)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return s.workoutQueries.GetWorkoutByID(ctx, workoutID)
+	return workout, events, nil
 }
 
-func (s *WorkoutService) DeleteWorkout(ctx context.Context, userID, workoutID int) error {
-	workout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
-	if err != nil {
-		return err
-	}
+// completeWorkout marks the workout completed and records any PRs it set.
+// Once tx commits, the caller finishes with afterCompletion.
+func completeWorkout(ctx context.Context, tx *sql.Tx, userID, workoutID int) ([]models.PREvent, error) {
+	workoutQueries := queries.NewWorkoutQueries(tx)
 
-	if workout.UserID != userID {
-		return fmt.Errorf("unauthorized")
+	if _, err := lockForTransition(ctx, workoutQueries, userID, workoutID, models.WorkoutStatusCompleted); err != nil {
+		return nil, err
 	}
 
-	if err := s.workoutQueries.DeleteWorkout(ctx, workoutID); err != nil {
-		return err
+	if err := workoutQueries.CompleteWorkout(ctx, workoutID); err != nil {
+		return nil, err
 	}
 
-	if workout.Status == "active" {
-		cacheKey := cache.GetActiveWorkoutKey(userID)
-		s.cache.Delete(ctx, cacheKey)
+	if err := workoutQueries.RenumberWorkoutSets(ctx, workoutID); err != nil {
+		return nil, err
 	}
 
-	return nil
+	return recordPREvents(ctx, queries.NewStatsQueries(tx), userID, workoutID)
 }
 
-func (s *WorkoutService) AddExercise(ctx context.Context, userID, workoutID int, name string, sets []models.SetInput) (*models.Exercise, error) {
+// afterCompletion evicts the caches a committed completion made stale,
+// tells the user's other clients and returns the completed workout.
+func (s *WorkoutService) afterCompletion(ctx context.Context, userID, workoutID int) (*models.WorkoutSession, error) {
+	if err := s.invalidateCachesOnComplete(ctx, userID); err != nil {
+		fmt.Printf("Failed to invalidate caches: %v\n", err)
+	}
+
+	s.publishWorkoutEvent(ctx, userID, models.WorkoutEventCompleted, workoutID)
+
 	workout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
 	if err != nil {
 		return nil, err
 	}
 
-	if workout.UserID != userID {
-		return nil, fmt.Errorf("unauthorized")
-	}
+	s.invalidateWorkoutStats(ctx, userID, workout)
 
-	if workout.Status != "active" {
-		return nil, fmt.Errorf("workout is not active")
-	}
+	return workout, nil
+}
+
+// PauseWorkout pauses an active workout. Time spent paused does not count
+// toward its active duration.
+func (s *WorkoutService) PauseWorkout(ctx context.Context, userID, workoutID int) (*models.WorkoutSession, error) {
+	return s.transition(ctx, userID, workoutID, models.WorkoutStatusPaused, func(workoutQueries *queries.WorkoutQueries) error {
+		return workoutQueries.PauseWorkout(ctx, workoutID)
+	})
+}
+
+func (s *WorkoutService) ResumeWorkout(ctx context.Context, userID, workoutID int) (*models.WorkoutSession, error) {
+	return s.transition(ctx, userID, workoutID, models.WorkoutStatusActive, func(workoutQueries *queries.WorkoutQueries) error {
+		return workoutQueries.ResumeWorkout(ctx, workoutID)
+	})
+}
+
+// AbandonWorkout discards an unfinished workout. It is kept, but never shows
+// up in stats or history.
+func (s *WorkoutService) AbandonWorkout(ctx context.Context, userID, workoutID int) (*models.WorkoutSession, error) {
+	return s.transition(ctx, userID, workoutID, models.WorkoutStatusAbandoned, func(workoutQueries *queries.WorkoutQueries) error {
+		return workoutQueries.AbandonWorkout(ctx, workoutID)
+	})
+}
+
+// transition moves the workout to status to with apply, then refreshes or
+// drops the cached active workout to match.
+func (s *WorkoutService) transition(ctx context.Context, userID, workoutID int, to string, apply func(*queries.WorkoutQueries) error) (*models.WorkoutSession, error) {
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+
+		if _, err := lockForTransition(ctx, workoutQueries, userID, workoutID, to); err != nil {
+			return err
+		}
 
-	orderIndex, err := s.workoutQueries.GetNextExerciseOrderIndex(ctx, workoutID)
+		return apply(workoutQueries)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	exercise, err := s.workoutQueries.CreateExercise(ctx, workoutID, name, orderIndex)
+	workout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
 	if err != nil {
 		return nil, err
 	}
 
-	for i, setInput := range sets {
-		set, err := s.workoutQueries.CreateSet(ctx, exercise.ID, i+1, setInput.Reps, setInput.Weight, setInput.IsBodyweight)
-		if err != nil {
-			return nil, err
+	if to == models.WorkoutStatusAbandoned {
+		if err := s.cache.Delete(ctx, cache.GetActiveWorkoutKey(userID), cache.GetRestTimerKey(userID)); err != nil {
+			fmt.Printf("Failed to invalidate caches: %v\n", err)
+		}
+		s.publishWorkoutEvent(ctx, userID, models.WorkoutEventAbandoned, workoutID)
+	} else {
+		if err := s.cacheActiveWorkout(ctx, userID, workout); err != nil {
+			fmt.Printf("Failed to cache active workout: %v\n", err)
 		}
-		exercise.Sets = append(exercise.Sets, *set)
+		s.publishWorkoutEvent(ctx, userID, models.WorkoutEventUpdated, workoutID)
 	}
 
-	if workout.Status == "active" {
-		updatedWorkout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
-		if err == nil {
-			s.cacheActiveWorkout(ctx, userID, updatedWorkout)
+	return workout, nil
+}
+
+// autoAbandonInterval is how often RunAutoAbandon looks for stale workouts.
+const autoAbandonInterval = 15 * time.Minute
+
+// RunAutoAbandon abandons workouts left unfinished for longer than the
+// configured threshold, checking every autoAbandonInterval until ctx is
+// cancelled.
+func (s *WorkoutService) RunAutoAbandon(ctx context.Context) {
+	ticker := time.NewTicker(autoAbandonInterval)
+	defer ticker.Stop()
+
+	for {
+		s.abandonStaleWorkouts(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
 		}
 	}
-
-	return exercise, nil
 }
 
-func (s *WorkoutService) UpdateExercise(ctx context.Context, userID, workoutID, exerciseID int, name *string, sets []models.SetInput) (*models.Exercise, error) {
-	workout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
+func (s *WorkoutService) abandonStaleWorkouts(ctx context.Context) {
+	threshold := time.Duration(s.config.AutoAbandonHours) * time.Hour
+
+	userIDs, err := s.workoutQueries.AbandonStaleWorkouts(ctx, time.Now().Add(-threshold))
 	if err != nil {
-		return nil, err
+		fmt.Printf("Failed to abandon stale workouts: %v\n", err)
+		return
 	}
 
-	if workout.UserID != userID {
-		return nil, fmt.Errorf("unauthorized")
+	for _, userID := range userIDs {
+		if err := s.cache.Delete(ctx, cache.GetActiveWorkoutKey(userID), cache.GetRestTimerKey(userID)); err != nil {
+			fmt.Printf("Failed to invalidate caches: %v\n", err)
+		}
+		s.publishWorkoutEvent(ctx, userID, models.WorkoutEventAbandoned, 0)
 	}
 
-	if workout.Status != "active" {
-		return nil, fmt.Errorf("workout is not active")
+	if len(userIDs) > 0 {
+		fmt.Printf("Abandoned %d stale workouts\n", len(userIDs))
 	}
+}
 
-	exercise, err := s.workoutQueries.GetExerciseByID(ctx, exerciseID)
-	if err != nil {
-		return nil, err
-	}
+func (s *WorkoutService) DeleteWorkout(ctx context.Context, userID, workoutID int, ifMatch *int) error {
+	var workout *models.WorkoutSession
 
-	if exercise.WorkoutSessionID != workoutID {
-		return nil, fmt.Errorf("exercise does not belong to this workout")
-	}
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
 
-	if name != nil && *name != "" {
-		if err := s.workoutQueries.UpdateExerciseName(ctx, exerciseID, *name); err != nil {
-			return nil, err
+		var err error
+		workout, err = workoutQueries.LockWorkout(ctx, workoutID)
+		if err != nil {
+			return err
 		}
-	}
 
-	if len(sets) > 0 {
-		if err := s.workoutQueries.DeleteSetsByExerciseID(ctx, exerciseID); err != nil {
-			return nil, err
+		if workout.UserID != userID {
+			return fmt.Errorf("unauthorized")
+		}
+
+		if err := checkWorkoutVersion(ctx, workoutQueries, workout, ifMatch); err != nil {
+			return err
 		}
 
-		for i, setInput := range sets {
-			_, err := s.workoutQueries.CreateSet(ctx, exerciseID, i+1, setInput.Reps, setInput.Weight, setInput.IsBodyweight)
+		// The exercises are needed afterwards to evict their progress.
+		if workout.Status == models.WorkoutStatusCompleted {
+			workout, err = workoutQueries.GetWorkoutByID(ctx, workoutID)
 			if err != nil {
-				return nil, err
+				return err
 			}
 		}
-	}
 
-	updatedExercise, err := s.workoutQueries.GetExerciseByID(ctx, exerciseID)
+		return workoutQueries.DeleteWorkout(ctx, workoutID)
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if workout.Status == "active" {
-		updatedWorkout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
-		if err == nil {
-			s.cacheActiveWorkout(ctx, userID, updatedWorkout)
-		}
+	switch workout.Status {
+	case models.WorkoutStatusActive, models.WorkoutStatusPaused:
+		s.cache.Delete(ctx, cache.GetActiveWorkoutKey(userID), cache.GetRestTimerKey(userID))
+		s.publishWorkoutEvent(ctx, userID, models.WorkoutEventUpdated, workoutID)
+	case models.WorkoutStatusCompleted:
+		s.invalidateWorkoutStats(ctx, userID, workout)
 	}
 
-	return updatedExercise, nil
+	return nil
 }
 
-func (s *WorkoutService) DeleteExercise(ctx context.Context, userID, workoutID, exerciseID int) error {
-	workout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
-	if err != nil {
-		return err
-	}
+func (s *WorkoutService) AddExercise(ctx context.Context, userID, workoutID int, definitionID *int, name string, notes *string, sets []models.SetInput) (*models.Exercise, error) {
+	var exercise *models.Exercise
+	var edit *workoutEdit
 
-	if workout.UserID != userID {
-		return fmt.Errorf("unauthorized")
-	}
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
 
-	if workout.Status != "active" {
-		return fmt.Errorf("workout is not active")
-	}
+		var err error
+		edit, err = lockEditableWorkout(ctx, workoutQueries, userID, workoutID)
+		if err != nil {
+			return err
+		}
 
-	exercise, err := s.workoutQueries.GetExerciseByID(ctx, exerciseID)
-	if err != nil {
-		return err
-	}
+		definition, err := resolveExercise(ctx, queries.NewCatalogQueries(tx), userID, definitionID, name)
+		if err != nil {
+			return err
+		}
 
-	if exercise.WorkoutSessionID != workoutID {
-		return fmt.Errorf("exercise does not belong to this workout")
-	}
+		if name == "" {
+			name = definition.Name
+		}
 
-	if err := s.workoutQueries.DeleteExercise(ctx, exerciseID); err != nil {
-		return err
-	}
+		orderIndex, err := workoutQueries.GetNextExerciseOrderIndex(ctx, workoutID)
+		if err != nil {
+			return err
+		}
 
-	if workout.Status == "active" {
-		updatedWorkout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
-		if err == nil {
-			s.cacheActiveWorkout(ctx, userID, updatedWorkout)
+		exercise, err = workoutQueries.CreateExercise(ctx, workoutID, definition.ID, name, orderIndex, notesValue(notes))
+		if err != nil {
+			return err
 		}
-	}
 
-	return nil
-}
+		exercise.Sets, err = createSets(ctx, workoutQueries, exercise.ID, sets)
+		if err != nil {
+			return err
+		}
 
-func (s *WorkoutService) cacheActiveWorkout(ctx context.Context, userID int, workout *models.WorkoutSession) error {
-	cacheKey := cache.GetActiveWorkoutKey(userID)
-	data, err := json.Marshal(workout)
+		return edit.record(ctx, workoutQueries, userID, models.RevisionAddExercise)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return s.cache.Set(ctx, cacheKey, data, cache.TTLActiveWorkout)
+	s.afterEdit(ctx, userID, edit)
+
+	return exercise, nil
 }
 
-func (s *WorkoutService) invalidateCachesOnComplete(ctx context.Context, userID int) error {
-	keys := []string{
-		cache.GetActiveWorkoutKey(userID),
-		cache.GetUserPRsKey(userID),
-	}
+func (s *WorkoutService) UpdateExercise(ctx context.Context, userID, workoutID, exerciseID int, definitionID *int, name, notes *string, sets []models.SetInput, ifMatch *int) (*models.Exercise, error) {
+	var updatedExercise *models.Exercise
+	var edit *workoutEdit
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+
+		var exercise *models.Exercise
+		var err error
+		edit, exercise, err = lockExercise(ctx, workoutQueries, userID, workoutID, exerciseID)
+		if err != nil {
+			return err
+		}
 
-	return s.cache.Delete(ctx, keys...)
+		if err := checkExerciseVersion(exercise, ifMatch); err != nil {
+			return err
+		}
+
+		if definitionID != nil || (name != nil && *name != "") {
+			displayName := ""
+			if name != nil {
+				displayName = *name
+			}
+
+			definition, err := resolveExercise(ctx, queries.NewCatalogQueries(tx), userID, definitionID, displayName)
+			if err != nil {
+				return err
+			}
+
+			if displayName == "" {
+				displayName = definition.Name
+			}
+
+			if err := workoutQueries.UpdateExerciseName(ctx, exerciseID, definition.ID, displayName); err != nil {
+				return err
+			}
+		}
+
+		if notes != nil {
+			if err := workoutQueries.UpdateExerciseNotes(ctx, exerciseID, notesValue(notes)); err != nil {
+				return err
+			}
+		}
+
+		if len(sets) > 0 {
+			if err := workoutQueries.DeleteSetsByExerciseID(ctx, exerciseID); err != nil {
+				return err
+			}
+
+			if _, err := createSets(ctx, workoutQueries, exerciseID, sets); err != nil {
+				return err
+			}
+		}
+
+		updatedExercise, err = workoutQueries.GetExerciseByID(ctx, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		return edit.record(ctx, workoutQueries, userID, models.RevisionUpdateExercise)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.afterEdit(ctx, userID, edit)
+
+	return updatedExercise, nil
+}
+
+func (s *WorkoutService) DeleteExercise(ctx context.Context, userID, workoutID, exerciseID int, ifMatch *int) error {
+	var edit *workoutEdit
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+
+		var exercise *models.Exercise
+		var err error
+		edit, exercise, err = lockExercise(ctx, workoutQueries, userID, workoutID, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		if err := checkExerciseVersion(exercise, ifMatch); err != nil {
+			return err
+		}
+
+		if err := workoutQueries.DeleteExercise(ctx, exerciseID); err != nil {
+			return err
+		}
+
+		return edit.record(ctx, workoutQueries, userID, models.RevisionDeleteExercise)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.afterEdit(ctx, userID, edit)
+
+	return nil
+}
+
+// AddSet appends a single set to an exercise, optionally inserting it at a
+// 1-based position. Existing sets keep their IDs; only their numbers shift.
+func (s *WorkoutService) AddSet(ctx context.Context, userID, workoutID, exerciseID int, input models.SetInput, position *int) (*models.Set, error) {
+	var set *models.Set
+	var edit *workoutEdit
+
+	// A completed set logged while the rest timer runs records the rest taken.
+	var rest *int
+	if isSetCompleted(input) && input.RestSeconds == nil {
+		rest = s.elapsedRest(ctx, userID, workoutID)
+		input.RestSeconds = rest
+	}
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+
+		var exercise *models.Exercise
+		var err error
+		edit, exercise, err = lockExercise(ctx, workoutQueries, userID, workoutID, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		set, err = workoutQueries.CreateSet(ctx, newSet(exerciseID, len(exercise.Sets)+1, input))
+		if err != nil {
+			return err
+		}
+
+		if position != nil && *position <= len(exercise.Sets) {
+			setIDs := insertSetID(setIDsOf(exercise.Sets), set.ID, *position)
+			if err := workoutQueries.ReorderSets(ctx, exerciseID, setIDs); err != nil {
+				return err
+			}
+
+			set, err = workoutQueries.GetSetByID(ctx, set.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return edit.record(ctx, workoutQueries, userID, models.RevisionAddSet)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rest != nil {
+		s.clearRestTimer(ctx, userID, workoutID)
+	}
+
+	s.afterEdit(ctx, userID, edit)
+
+	return set, nil
+}
+
+// UpdateSet applies a partial update to one set in place, keeping its ID and
+// created_at. A new set number moves the set and renumbers its siblings.
+func (s *WorkoutService) UpdateSet(ctx context.Context, userID, workoutID, exerciseID, setID int, req models.UpdateSetRequest) (*models.Set, error) {
+	var set *models.Set
+	var edit *workoutEdit
+
+	// Ticking a set off while the rest timer runs records the rest taken.
+	var rest *int
+	if req.IsCompleted != nil && *req.IsCompleted && req.RestSeconds == nil {
+		rest = s.elapsedRest(ctx, userID, workoutID)
+	}
+	restRecorded := false
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+
+		var exercise *models.Exercise
+		var err error
+		edit, exercise, err = lockExercise(ctx, workoutQueries, userID, workoutID, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		existing := findSet(exercise.Sets, setID)
+		if existing == nil {
+			return fmt.Errorf("set not found")
+		}
+
+		if rest != nil && !existing.IsCompleted {
+			existing.RestSeconds = rest
+			restRecorded = true
+		}
+
+		if req.Reps != nil {
+			existing.Reps = *req.Reps
+		}
+		if req.Weight != nil {
+			existing.Weight = req.Weight
+		}
+		if req.WeightUnit != nil {
+			existing.WeightUnit = *req.WeightUnit
+		}
+		if req.IsBodyweight != nil {
+			existing.IsBodyweight = *req.IsBodyweight
+		}
+		if req.IsCompleted != nil {
+			existing.IsCompleted = *req.IsCompleted
+		}
+		if req.SetType != nil {
+			existing.SetType = *req.SetType
+		}
+		if req.RPE != nil {
+			existing.RPE = req.RPE
+		}
+		if req.RIR != nil {
+			existing.RIR = req.RIR
+		}
+		if req.Tempo != nil {
+			existing.Tempo = req.Tempo
+		}
+		if req.RestSeconds != nil {
+			existing.RestSeconds = req.RestSeconds
+		}
+		if req.Notes != nil {
+			existing.Notes = notesValue(req.Notes)
+		}
+
+		if existing.IsBodyweight {
+			existing.Weight = nil
+		} else if existing.Weight == nil || *existing.Weight <= 0 {
+			return fmt.Errorf("weight is required for non-bodyweight sets")
+		}
+
+		if _, err := workoutQueries.UpdateSet(ctx, existing); err != nil {
+			return err
+		}
+
+		if req.SetNumber != nil && *req.SetNumber != existing.SetNumber {
+			setIDs := insertSetID(removeSetID(setIDsOf(exercise.Sets), setID), setID, *req.SetNumber)
+			if err := workoutQueries.ReorderSets(ctx, exerciseID, setIDs); err != nil {
+				return err
+			}
+		}
+
+		set, err = workoutQueries.GetSetByID(ctx, setID)
+		if err != nil {
+			return err
+		}
+
+		return edit.record(ctx, workoutQueries, userID, models.RevisionUpdateSet)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if restRecorded {
+		s.clearRestTimer(ctx, userID, workoutID)
+	}
+
+	s.afterEdit(ctx, userID, edit)
+
+	return set, nil
+}
+
+func (s *WorkoutService) DeleteSet(ctx context.Context, userID, workoutID, exerciseID, setID int) error {
+	var edit *workoutEdit
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+
+		var exercise *models.Exercise
+		var err error
+		edit, exercise, err = lockExercise(ctx, workoutQueries, userID, workoutID, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		if findSet(exercise.Sets, setID) == nil {
+			return fmt.Errorf("set not found")
+		}
+
+		if err := workoutQueries.DeleteSet(ctx, setID); err != nil {
+			return err
+		}
+
+		if err := workoutQueries.RenumberSets(ctx, exerciseID); err != nil {
+			return err
+		}
+
+		return edit.record(ctx, workoutQueries, userID, models.RevisionDeleteSet)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.afterEdit(ctx, userID, edit)
+
+	return nil
+}
+
+// ReorderSets renumbers an exercise's sets to follow setIDs, which must list
+// every set of the exercise exactly once.
+func (s *WorkoutService) ReorderSets(ctx context.Context, userID, workoutID, exerciseID int, setIDs []int) (*models.Exercise, error) {
+	var updatedExercise *models.Exercise
+	var edit *workoutEdit
+
+	err := queries.WithTx(ctx, s.db, func(tx *sql.Tx) error {
+		workoutQueries := queries.NewWorkoutQueries(tx)
+
+		var exercise *models.Exercise
+		var err error
+		edit, exercise, err = lockExercise(ctx, workoutQueries, userID, workoutID, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		if len(setIDs) != len(exercise.Sets) {
+			return fmt.Errorf("set order must include every set exactly once")
+		}
+
+		seen := map[int]bool{}
+		for _, id := range setIDs {
+			if seen[id] || findSet(exercise.Sets, id) == nil {
+				return fmt.Errorf("set order must include every set exactly once")
+			}
+			seen[id] = true
+		}
+
+		if err := workoutQueries.ReorderSets(ctx, exerciseID, setIDs); err != nil {
+			return err
+		}
+
+		updatedExercise, err = workoutQueries.GetExerciseByID(ctx, exerciseID)
+		if err != nil {
+			return err
+		}
+
+		return edit.record(ctx, workoutQueries, userID, models.RevisionReorderSets)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.afterEdit(ctx, userID, edit)
+
+	return updatedExercise, nil
+}
+
+// workoutTransitions lists the statuses each status may move to. Completed
+// and abandoned workouts are final.
+var workoutTransitions = map[string][]string{
+	models.WorkoutStatusActive: {models.WorkoutStatusPaused, models.WorkoutStatusCompleted, models.WorkoutStatusAbandoned},
+	models.WorkoutStatusPaused: {models.WorkoutStatusActive, models.WorkoutStatusCompleted, models.WorkoutStatusAbandoned},
+}
+
+func canTransition(from, to string) bool {
+	for _, status := range workoutTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// lockForTransition locks the workout row for the rest of the transaction
+// and checks that the user may move it to status to.
+func lockForTransition(ctx context.Context, workoutQueries *queries.WorkoutQueries, userID, workoutID int, to string) (*models.WorkoutSession, error) {
+	workout, err := workoutQueries.LockWorkout(ctx, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workout.UserID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	if !canTransition(workout.Status, to) {
+		return nil, fmt.Errorf("invalid transition: a %s workout cannot become %s", workout.Status, to)
+	}
+
+	return workout, nil
+}
+
+// workoutEdit is an edit in progress on a locked workout. before is a full
+// snapshot taken only for completed workouts, whose edits are recorded as
+// revisions; unfinished workouts are simply re-cached once the edit commits.
+type workoutEdit struct {
+	workout    *models.WorkoutSession
+	exerciseID int
+	before     *models.WorkoutSession
+	after      *models.WorkoutSession
+}
+
+// lockEditableWorkout locks the workout row for the rest of the transaction
+// and checks that the user may edit it.
+func lockEditableWorkout(ctx context.Context, workoutQueries *queries.WorkoutQueries, userID, workoutID int) (*workoutEdit, error) {
+	workout, err := workoutQueries.LockWorkout(ctx, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	if workout.UserID != userID {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	edit := &workoutEdit{workout: workout}
+
+	switch workout.Status {
+	case models.WorkoutStatusActive, models.WorkoutStatusPaused:
+	case models.WorkoutStatusCompleted:
+		edit.before, err = workoutQueries.GetWorkoutByID(ctx, workoutID)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("workout cannot be edited")
+	}
+
+	return edit, nil
+}
+
+// lockExercise locks the workout via lockEditableWorkout and loads the
+// exercise, checking that it belongs to that workout.
+func lockExercise(ctx context.Context, workoutQueries *queries.WorkoutQueries, userID, workoutID, exerciseID int) (*workoutEdit, *models.Exercise, error) {
+	edit, err := lockEditableWorkout(ctx, workoutQueries, userID, workoutID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	exercise, err := workoutQueries.GetExerciseByID(ctx, exerciseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if exercise.WorkoutSessionID != workoutID {
+		return nil, nil, fmt.Errorf("exercise does not belong to this workout")
+	}
+
+	edit.exerciseID = exerciseID
+	return edit, exercise, nil
+}
+
+// checkWorkoutVersion fails with a VersionConflictError unless ifMatch is nil
+// or the workout's current version.
+func checkWorkoutVersion(ctx context.Context, workoutQueries *queries.WorkoutQueries, workout *models.WorkoutSession, ifMatch *int) error {
+	if ifMatch == nil || *ifMatch == workout.Version {
+		return nil
+	}
+
+	current, err := workoutQueries.GetWorkoutByID(ctx, workout.ID)
+	if err != nil {
+		return err
+	}
+
+	return &VersionConflictError{
+		Resource:        "workout",
+		ID:              workout.ID,
+		ExpectedVersion: *ifMatch,
+		CurrentVersion:  workout.Version,
+		Workout:         current,
+	}
+}
+
+// checkExerciseVersion fails with a VersionConflictError unless ifMatch is
+// nil or the exercise's current version.
+func checkExerciseVersion(exercise *models.Exercise, ifMatch *int) error {
+	if ifMatch == nil || *ifMatch == exercise.Version {
+		return nil
+	}
+
+	return &VersionConflictError{
+		Resource:        "exercise",
+		ID:              exercise.ID,
+		ExpectedVersion: *ifMatch,
+		CurrentVersion:  exercise.Version,
+		Exercise:        exercise,
+	}
+}
+
+// record bumps the versions of the edited workout and exercise and, if the
+// workout is completed, stores the edit as a revision. It must run inside the
+// edit's transaction, after every change has been made.
+func (e *workoutEdit) record(ctx context.Context, workoutQueries *queries.WorkoutQueries, userID int, action string) error {
+	if err := workoutQueries.BumpVersions(ctx, e.workout.ID, e.exerciseID); err != nil {
+		return err
+	}
+
+	if e.before == nil {
+		return nil
+	}
+
+	after, err := workoutQueries.GetWorkoutByID(ctx, e.workout.ID)
+	if err != nil {
+		return err
+	}
+	e.after = after
+
+	return workoutQueries.CreateRevision(ctx, userID, action, e.before, after)
+}
+
+// afterEdit runs once an edit has committed. Unfinished workouts are
+// re-cached; edits to completed workouts evict the stats they fed into.
+func (s *WorkoutService) afterEdit(ctx context.Context, userID int, edit *workoutEdit) {
+	if edit.before == nil {
+		s.refreshActiveWorkout(ctx, userID, edit.workout.ID)
+		return
+	}
+
+	s.invalidateWorkoutStats(ctx, userID, edit.before, edit.after)
+}
+
+const (
+	// maxClockSkew lets a client whose clock runs slightly fast record a
+	// workout that just ended.
+	maxClockSkew = time.Minute
+
+	maxWorkoutDuration = 24 * time.Hour
+)
+
+func checkWorkoutTimes(startedAt, completedAt time.Time) error {
+	if !completedAt.After(startedAt) {
+		return fmt.Errorf("invalid workout times: a workout must end after it starts")
+	}
+	if completedAt.After(time.Now().Add(maxClockSkew)) {
+		return fmt.Errorf("invalid workout times: a workout cannot end in the future")
+	}
+	if completedAt.Sub(startedAt) > maxWorkoutDuration {
+		return fmt.Errorf("invalid workout times: a workout cannot last longer than 24 hours")
+	}
+	return nil
+}
+
+// checkOverlap fails with a WorkoutConflictError if any of the user's other
+// workouts overlaps [startedAt, completedAt).
+func checkOverlap(ctx context.Context, workoutQueries *queries.WorkoutQueries, userID, workoutID int, startedAt, completedAt time.Time) error {
+	overlapping, err := workoutQueries.FindOverlappingWorkout(ctx, userID, workoutID, startedAt, completedAt)
+	if err != nil {
+		return err
+	}
+
+	if overlapping != nil {
+		return &WorkoutConflictError{
+			WorkoutID: overlapping.ID,
+			Message:   "workout overlaps an existing workout",
+		}
+	}
+
+	return nil
+}
+
+// createExercisesFromRoutine copies each routine exercise into the workout
+// with its target sets as unchecked placeholders.
+func createExercisesFromRoutine(ctx context.Context, workoutQueries *queries.WorkoutQueries, workoutID int, routine *models.Routine) ([]models.Exercise, error) {
+	exercises := []models.Exercise{}
+	for i, target := range routine.Exercises {
+		exercise, err := workoutQueries.CreateExercise(ctx, workoutID, target.ExerciseDefinitionID, target.Name, i, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		weight := target.TargetWeight
+		if target.IsBodyweight {
+			weight = nil
+		}
+
+		for n := 1; n <= target.TargetSets; n++ {
+			set, err := workoutQueries.CreateSet(ctx, &models.Set{
+				ExerciseID:   exercise.ID,
+				SetNumber:    n,
+				Reps:         target.TargetReps,
+				Weight:       weight,
+				WeightUnit:   target.WeightUnit,
+				IsBodyweight: target.IsBodyweight,
+				IsCompleted:  false,
+				SetType:      models.SetTypeNormal,
+			})
+			if err != nil {
+				return nil, err
+			}
+			exercise.Sets = append(exercise.Sets, *set)
+		}
+
+		exercises = append(exercises, *exercise)
+	}
+
+	return exercises, nil
+}
+
+func createSets(ctx context.Context, workoutQueries *queries.WorkoutQueries, exerciseID int, inputs []models.SetInput) ([]models.Set, error) {
+	sets := []models.Set{}
+	for i, setInput := range inputs {
+		set, err := workoutQueries.CreateSet(ctx, newSet(exerciseID, i+1, setInput))
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, *set)
+	}
+
+	return sets, nil
+}
+
+// refreshActiveWorkout re-caches the workout after a committed edit.
+func (s *WorkoutService) refreshActiveWorkout(ctx context.Context, userID, workoutID int) {
+	updatedWorkout, err := s.workoutQueries.GetWorkoutByID(ctx, workoutID)
+	if err != nil {
+		return
+	}
+
+	if err := s.cacheActiveWorkout(ctx, userID, updatedWorkout); err != nil {
+		fmt.Printf("Failed to cache active workout: %v\n", err)
+	}
+
+	s.publishWorkoutEvent(ctx, userID, models.WorkoutEventUpdated, workoutID)
+}
+
+func findSet(sets []models.Set, setID int) *models.Set {
+	for i := range sets {
+		if sets[i].ID == setID {
+			return &sets[i]
+		}
+	}
+	return nil
+}
+
+func setIDsOf(sets []models.Set) []int {
+	ids := make([]int, 0, len(sets))
+	for _, set := range sets {
+		ids = append(ids, set.ID)
+	}
+	return ids
+}
+
+func removeSetID(ids []int, setID int) []int {
+	remaining := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id != setID {
+			remaining = append(remaining, id)
+		}
+	}
+	return remaining
+}
+
+// insertSetID places setID at a 1-based position, clamped to the list bounds.
+func insertSetID(ids []int, setID, position int) []int {
+	index := position - 1
+	if index < 0 {
+		index = 0
+	}
+	if index > len(ids) {
+		index = len(ids)
+	}
+
+	ordered := make([]int, 0, len(ids)+1)
+	ordered = append(ordered, ids[:index]...)
+	ordered = append(ordered, setID)
+	return append(ordered, ids[index:]...)
+}
+
+func newSet(exerciseID, setNumber int, input models.SetInput) *models.Set {
+	setType := input.SetType
+	if setType == "" {
+		setType = models.SetTypeNormal
+	}
+
+	weightUnit := input.WeightUnit
+	if weightUnit == "" {
+		weightUnit = units.DefaultUnit
+	}
+
+	return &models.Set{
+		ExerciseID:   exerciseID,
+		SetNumber:    setNumber,
+		Reps:         input.Reps,
+		Weight:       input.Weight,
+		WeightUnit:   weightUnit,
+		IsBodyweight: input.IsBodyweight,
+		IsCompleted:  isSetCompleted(input),
+		SetType:      setType,
+		RPE:          input.RPE,
+		RIR:          input.RIR,
+		Tempo:        input.Tempo,
+		RestSeconds:  input.RestSeconds,
+		Notes:        notesValue(input.Notes),
+	}
+}
+
+// notesValue stores empty notes as NULL, which is how clients clear them.
+func notesValue(notes *string) *string {
+	if notes == nil || *notes == "" {
+		return nil
+	}
+	return notes
+}
+
+// isSetCompleted treats sets as performed unless the client explicitly sends
+// them as unchecked placeholders.
+func isSetCompleted(input models.SetInput) bool {
+	return input.IsCompleted == nil || *input.IsCompleted
+}
+
+func (s *WorkoutService) cacheActiveWorkout(ctx context.Context, userID int, workout *models.WorkoutSession) error {
+	cacheKey := cache.GetActiveWorkoutKey(userID)
+	data, err := json.Marshal(workout)
+	if err != nil {
+		return err
+	}
+
+	return s.cache.Set(ctx, cacheKey, data, cache.TTLActiveWorkout)
+}
+
+// invalidateWorkoutStats evicts the stats a completed workout feeds into:
+// the summaries of the weeks it ended in, in the user's timezone, the PR
+// entries, and the progress series of its exercises. Pass the workout as it
+// was before and after an edit so both weeks and all exercises are covered.
+func (s *WorkoutService) invalidateWorkoutStats(ctx context.Context, userID int, workouts ...*models.WorkoutSession) {
+	loc := s.userLocation(ctx, userID)
+
+	weekKeys := []string{}
+	patterns := []string{cache.GetUserPRsPattern(userID)}
+	seen := map[int]bool{}
+
+	for _, workout := range workouts {
+		if workout.CompletedAt != nil {
+			year, week := workout.CompletedAt.In(loc).ISOWeek()
+			weekKeys = append(weekKeys, cache.GetWeeklySummaryKey(userID, loc.String(), fmt.Sprintf("%d-W%02d", year, week)))
+		}
+
+		for _, exercise := range workout.Exercises {
+			if !seen[exercise.ExerciseDefinitionID] {
+				seen[exercise.ExerciseDefinitionID] = true
+				patterns = append(patterns, cache.GetExerciseProgressDefinitionPattern(userID, exercise.ExerciseDefinitionID))
+			}
+		}
+	}
+
+	if err := s.cache.Delete(ctx, weekKeys...); err != nil {
+		fmt.Printf("Failed to invalidate caches: %v\n", err)
+	}
+
+	for _, pattern := range patterns {
+		if err := s.cache.DeletePattern(ctx, pattern); err != nil {
+			fmt.Printf("Failed to invalidate caches: %v\n", err)
+		}
+	}
+}
+
+// userLocation loads the timezone stats are bucketed in for the user,
+// falling back to UTC like the handlers do.
+func (s *WorkoutService) userLocation(ctx context.Context, userID int) *time.Location {
+	user, err := queries.NewUserQueries(s.db).GetUserByID(ctx, userID)
+	if err != nil {
+		fmt.Printf("Failed to load user: %v\n", err)
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// invalidateCachesOnComplete drops the finished workout's active state;
+// invalidateWorkoutStats covers the stats it fed into.
+func (s *WorkoutService) invalidateCachesOnComplete(ctx context.Context, userID int) error {
+	return s.cache.Delete(ctx, cache.GetActiveWorkoutKey(userID), cache.GetRestTimerKey(userID))
 }
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/database/queries"
+	"github.com/damion-14/cadence/backend/internal/models"
+	"github.com/damion-14/cadence/backend/internal/units"
+)
+
+// The benchmarks run against the Postgres database in TEST_DATABASE_URL,
+// migrating it and seeding a throwaway user, and are skipped without one.
+// Each reports queries/op alongside time so the N+1 loader and the batched
+// one can be compared directly:
+//
+//	TEST_DATABASE_URL=postgres://... go test -run '^$' -bench Workout ./internal/services
+
+const (
+	benchWorkouts  = 20
+	benchExercises = 8
+	benchSets      = 4
+)
+
+// countingQuerier counts the statements sent through it.
+type countingQuerier struct {
+	queries.Querier
+	count int
+}
+
+func (c *countingQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	c.count++
+	return c.Querier.ExecContext(ctx, query, args...)
+}
+
+func (c *countingQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.count++
+	return c.Querier.QueryContext(ctx, query, args...)
+}
+
+func (c *countingQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	c.count++
+	return c.Querier.QueryRowContext(ctx, query, args...)
+}
+
+func BenchmarkGetWorkout(b *testing.B) {
+	ctx := context.Background()
+	db, workoutIDs := seedBenchWorkouts(b)
+
+	b.Run("n+1", func(b *testing.B) {
+		counter := &countingQuerier{Querier: db}
+		workoutQueries := queries.NewWorkoutQueries(counter)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := loadWorkoutPerExercise(ctx, counter, workoutQueries, workoutIDs[i%len(workoutIDs)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(counter.count)/float64(b.N), "queries/op")
+	})
+
+	b.Run("batched", func(b *testing.B) {
+		counter := &countingQuerier{Querier: db}
+		s := &WorkoutService{db: db, workoutQueries: queries.NewWorkoutQueries(counter)}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, err := s.GetWorkout(ctx, workoutIDs[i%len(workoutIDs)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(counter.count)/float64(b.N), "queries/op")
+	})
+}
+
+func BenchmarkListWorkouts(b *testing.B) {
+	ctx := context.Background()
+	db, workoutIDs := seedBenchWorkouts(b)
+
+	b.Run("n+1", func(b *testing.B) {
+		counter := &countingQuerier{Querier: db}
+		workoutQueries := queries.NewWorkoutQueries(counter)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, workoutID := range workoutIDs {
+				if err := loadWorkoutPerExercise(ctx, counter, workoutQueries, workoutID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+		b.ReportMetric(float64(counter.count)/float64(b.N), "queries/op")
+	})
+
+	b.Run("batched", func(b *testing.B) {
+		counter := &countingQuerier{Querier: db}
+		workoutQueries := queries.NewWorkoutQueries(counter)
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			workouts, err := workoutQueries.GetWorkoutsByIDs(ctx, workoutIDs)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if len(workouts) != len(workoutIDs) {
+				b.Fatalf("loaded %d workouts, want %d", len(workouts), len(workoutIDs))
+			}
+		}
+		b.ReportMetric(float64(counter.count)/float64(b.N), "queries/op")
+	})
+}
+
+// loadWorkoutPerExercise loads a workout the way GetWorkoutByID did before
+// loading was batched: the session, its exercises, then one set query per
+// exercise and finally its pauses.
+func loadWorkoutPerExercise(ctx context.Context, db queries.Querier, workoutQueries *queries.WorkoutQueries, workoutID int) error {
+	var id int
+	if err := db.QueryRowContext(ctx, `SELECT id FROM workout_sessions WHERE id = $1`, workoutID).Scan(&id); err != nil {
+		return err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT id FROM exercises WHERE workout_session_id = $1 ORDER BY order_index ASC`, workoutID)
+	if err != nil {
+		return err
+	}
+
+	exerciseIDs := []int{}
+	for rows.Next() {
+		var exerciseID int
+		if err := rows.Scan(&exerciseID); err != nil {
+			rows.Close()
+			return err
+		}
+		exerciseIDs = append(exerciseIDs, exerciseID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, exerciseID := range exerciseIDs {
+		if _, err := workoutQueries.GetSetsByExerciseID(ctx, exerciseID); err != nil {
+			return err
+		}
+	}
+
+	rows, err = db.QueryContext(ctx, `SELECT id FROM workout_pauses WHERE workout_session_id = $1`, workoutID)
+	if err != nil {
+		return err
+	}
+	rows.Close()
+	return rows.Err()
+}
+
+// seedBenchWorkouts creates a user with benchWorkouts completed workouts and
+// returns their IDs. The user and everything under it is deleted when the
+// benchmark ends.
+func seedBenchWorkouts(b *testing.B) (*sql.DB, []int) {
+	b.Helper()
+
+	ctx := context.Background()
+	db := openTestDB(b)
+	user := createTestUser(b, db)
+
+	definitions, err := queries.NewCatalogQueries(db).ListDefinitions(ctx, user.ID, benchExercises)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if len(definitions) == 0 {
+		b.Fatal("the exercise catalog is empty")
+	}
+
+	workoutQueries := queries.NewWorkoutQueries(db)
+
+	start := time.Now().Add(-benchWorkouts * 24 * time.Hour)
+	workouts := make([]models.WorkoutSession, benchWorkouts)
+	for i := range workouts {
+		startedAt := start.Add(time.Duration(i) * 24 * time.Hour)
+		completedAt := startedAt.Add(time.Hour)
+		workouts[i] = models.WorkoutSession{Name: fmt.Sprintf("Bench %d", i), StartedAt: startedAt, CompletedAt: &completedAt}
+	}
+
+	workoutIDs, err := workoutQueries.CreateCompletedWorkouts(ctx, user.ID, workouts)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	exercises := []models.Exercise{}
+	for _, workoutID := range workoutIDs {
+		for i := 0; i < benchExercises; i++ {
+			definition := definitions[i%len(definitions)]
+			exercises = append(exercises, models.Exercise{
+				WorkoutSessionID:     workoutID,
+				ExerciseDefinitionID: definition.ID,
+				Name:                 definition.Name,
+				OrderIndex:           i,
+			})
+		}
+	}
+
+	exerciseIDs, err := workoutQueries.CreateExercises(ctx, exercises)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	weight := 60.0
+	sets := []models.Set{}
+	for _, exerciseID := range exerciseIDs {
+		for n := 1; n <= benchSets; n++ {
+			set := newSet(exerciseID, n, models.SetInput{Reps: 8, Weight: &weight, WeightUnit: units.Kilograms})
+			set.IsCompleted = true
+			sets = append(sets, *set)
+		}
+	}
+
+	if err := workoutQueries.CreateSets(ctx, sets); err != nil {
+		b.Fatal(err)
+	}
+
+	return db, workoutIDs
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damion-14/cadence/backend/internal/database/migrations"
+	"github.com/damion-14/cadence/backend/internal/database/queries"
+	"github.com/damion-14/cadence/backend/internal/models"
+)
+
+// openTestDB connects to and migrates the Postgres database in
+// TEST_DATABASE_URL, skipping the test without one.
+func openTestDB(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		tb.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		tb.Fatal(err)
+	}
+
+	return db
+}
+
+// createTestUser creates a throwaway user that is deleted, along with
+// everything under it, when the test ends.
+func createTestUser(tb testing.TB, db *sql.DB) *models.User {
+	tb.Helper()
+
+	email := fmt.Sprintf("test-%d@example.com", time.Now().UnixNano())
+	user, err := queries.NewUserQueries(db).CreateUser(context.Background(), email, "x", "test")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() {
+		if _, err := db.ExecContext(context.Background(), `DELETE FROM users WHERE id = $1`, user.ID); err != nil {
+			tb.Errorf("failed to remove test user: %v", err)
+		}
+	})
+
+	return user
+}
+
+func TestCanTransition(t *testing.T) {
+	statuses := []string{
+		models.WorkoutStatusActive,
+		models.WorkoutStatusPaused,
+		models.WorkoutStatusCompleted,
+		models.WorkoutStatusAbandoned,
+	}
+
+	allowed := map[[2]string]bool{
+		{models.WorkoutStatusActive, models.WorkoutStatusPaused}:    true,
+		{models.WorkoutStatusActive, models.WorkoutStatusCompleted}: true,
+		{models.WorkoutStatusActive, models.WorkoutStatusAbandoned}: true,
+		{models.WorkoutStatusPaused, models.WorkoutStatusActive}:    true,
+		{models.WorkoutStatusPaused, models.WorkoutStatusCompleted}: true,
+		{models.WorkoutStatusPaused, models.WorkoutStatusAbandoned}: true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			if got, want := canTransition(from, to), allowed[[2]string{from, to}]; got != want {
+				t.Errorf("canTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+
+	if canTransition("", models.WorkoutStatusActive) {
+		t.Error("an unknown status may transition")
+	}
+}
+
+func TestCheckWorkoutTimes(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		startedAt   time.Time
+		completedAt time.Time
+		wantErr     string
+	}{
+		{"an hour yesterday", now.Add(-25 * time.Hour), now.Add(-24 * time.Hour), ""},
+		{"ends now", now.Add(-time.Hour), now, ""},
+		{"ends within the clock skew", now.Add(-time.Hour), now.Add(50 * time.Second), ""},
+		{"ends past the clock skew", now.Add(-time.Hour), now.Add(70 * time.Second), "in the future"},
+		{"exactly 24 hours", now.Add(-48 * time.Hour), now.Add(-24 * time.Hour), ""},
+		{"over 24 hours", now.Add(-48*time.Hour - time.Second), now.Add(-24 * time.Hour), "longer than 24 hours"},
+		{"ends as it starts", now.Add(-time.Hour), now.Add(-time.Hour), "end after it starts"},
+		{"ends before it starts", now.Add(-time.Hour), now.Add(-2 * time.Hour), "end after it starts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkWorkoutTimes(tt.startedAt, tt.completedAt)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkWorkoutTimes: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("checkWorkoutTimes error = %v, want %q", err, tt.wantErr)
+			}
+			if !strings.HasPrefix(err.Error(), "invalid workout times") {
+				t.Fatalf("error %q lacks the prefix handlers match on", err)
+			}
+		})
+	}
+}
+
+func TestCheckOverlap(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	user := createTestUser(t, db)
+	workoutQueries := queries.NewWorkoutQueries(db)
+
+	start := time.Now().Add(-72 * time.Hour).Truncate(time.Hour)
+	end := start.Add(time.Hour)
+	abandonedStart := start.Add(24 * time.Hour)
+
+	ids, err := workoutQueries.CreateCompletedWorkouts(ctx, user.ID, []models.WorkoutSession{
+		{Name: "Existing", StartedAt: start, CompletedAt: &end},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	existingID := ids[0]
+
+	abandoned, err := workoutQueries.CreateWorkout(ctx, user.ID, "Abandoned")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE workout_sessions SET status = 'abandoned', started_at = $2 WHERE id = $1`, abandoned.ID, abandonedStart); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		workoutID   int
+		startedAt   time.Time
+		completedAt time.Time
+		wantID      int
+	}{
+		{"inside", 0, start.Add(15 * time.Minute), start.Add(45 * time.Minute), existingID},
+		{"straddles the start", 0, start.Add(-30 * time.Minute), start.Add(30 * time.Minute), existingID},
+		{"covers it", 0, start.Add(-time.Hour), end.Add(time.Hour), existingID},
+		{"ends as it starts", 0, start.Add(-time.Hour), start, 0},
+		{"starts as it ends", 0, end, end.Add(time.Hour), 0},
+		{"the workout itself", existingID, start, end, 0},
+		{"over an abandoned workout", 0, abandonedStart, abandonedStart.Add(time.Hour), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkOverlap(ctx, workoutQueries, user.ID, tt.workoutID, tt.startedAt, tt.completedAt)
+			if tt.wantID == 0 {
+				if err != nil {
+					t.Fatalf("checkOverlap: %v", err)
+				}
+				return
+			}
+
+			var conflict *WorkoutConflictError
+			if !errors.As(err, &conflict) || conflict.WorkoutID != tt.wantID {
+				t.Fatalf("checkOverlap error = %v, want a conflict with workout %d", err, tt.wantID)
+			}
+		})
+	}
+}
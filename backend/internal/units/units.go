@@ -0,0 +1,61 @@
+// Package units converts weights between kilograms and pounds and rounds
+// them for display.
+package units
+
+import "math"
+
+const (
+	Kilograms = "kg"
+	Pounds    = "lb"
+
+	DefaultUnit = Pounds
+
+	kgPerLb = 0.45359237
+)
+
+// Plate increments used when rounding converted set weights: the smallest
+// common pair of fractional plates in each system.
+const (
+	kgPlateIncrement = 0.5
+	lbPlateIncrement = 1.0
+)
+
+func IsValid(unit string) bool {
+	return unit == Kilograms || unit == Pounds
+}
+
+// Convert changes value from one unit to another without rounding.
+func Convert(value float64, from, to string) float64 {
+	if from == to {
+		return value
+	}
+	if from == Pounds {
+		return value * kgPerLb
+	}
+	return value / kgPerLb
+}
+
+// ToPlate converts a single set weight and rounds it to the nearest loadable
+// increment. Weights already in the target unit are returned unchanged.
+func ToPlate(value float64, from, to string) float64 {
+	if from == to {
+		return value
+	}
+	return RoundToPlate(Convert(value, from, to), to)
+}
+
+// RoundToPlate rounds a weight in unit to the nearest loadable increment.
+func RoundToPlate(value float64, unit string) float64 {
+	increment := lbPlateIncrement
+	if unit == Kilograms {
+		increment = kgPlateIncrement
+	}
+
+	return math.Round(value/increment) * increment
+}
+
+// ToDisplay converts derived figures such as volume or e1RM, which are not
+// loaded on a bar, and rounds them to one decimal place.
+func ToDisplay(value float64, from, to string) float64 {
+	return math.Round(Convert(value, from, to)*10) / 10
+}
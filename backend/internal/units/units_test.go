@@ -0,0 +1,122 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIsValid(t *testing.T) {
+	tests := []struct {
+		unit string
+		want bool
+	}{
+		{Kilograms, true},
+		{Pounds, true},
+		{"lbs", false},
+		{"KG", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsValid(tt.unit); got != tt.want {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{"same unit", 100, Kilograms, Kilograms, 100},
+		{"lb to kg", 100, Pounds, Kilograms, 45.359237},
+		{"kg to lb", 45.359237, Kilograms, Pounds, 100},
+		{"zero", 0, Pounds, Kilograms, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Convert(tt.value, tt.from, tt.to); math.Abs(got-tt.want) > 1e-9 {
+				t.Fatalf("Convert(%v, %s, %s) = %v, want %v", tt.value, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	for _, value := range []float64{2.5, 20, 61.25, 142.5, 315} {
+		got := Convert(Convert(value, Kilograms, Pounds), Pounds, Kilograms)
+		if math.Abs(got-value) > 1e-9 {
+			t.Errorf("kg -> lb -> kg of %v = %v", value, got)
+		}
+	}
+}
+
+func TestToPlate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{"same unit is untouched", 101.3, Kilograms, Kilograms, 101.3},
+		{"225 lb to kg", 225, Pounds, Kilograms, 102},
+		{"135 lb to kg", 135, Pounds, Kilograms, 61},
+		{"100 kg to lb", 100, Kilograms, Pounds, 220},
+		{"20 kg to lb", 20, Kilograms, Pounds, 44},
+		{"2.5 kg to lb", 2.5, Kilograms, Pounds, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToPlate(tt.value, tt.from, tt.to); got != tt.want {
+				t.Fatalf("ToPlate(%v, %s, %s) = %v, want %v", tt.value, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundToPlate(t *testing.T) {
+	tests := []struct {
+		value float64
+		unit  string
+		want  float64
+	}{
+		{102.06, Kilograms, 102},
+		{102.3, Kilograms, 102.5},
+		{102.75, Kilograms, 103},
+		{220.46, Pounds, 220},
+		{220.5, Pounds, 221},
+		{0.2, Kilograms, 0},
+	}
+
+	for _, tt := range tests {
+		if got := RoundToPlate(tt.value, tt.unit); got != tt.want {
+			t.Errorf("RoundToPlate(%v, %s) = %v, want %v", tt.value, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestToDisplay(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		from, to string
+		want     float64
+	}{
+		{"volume kg to lb", 5000, Kilograms, Pounds, 11023.1},
+		{"e1rm lb to kg", 300, Pounds, Kilograms, 136.1},
+		{"same unit rounds", 123.456, Kilograms, Kilograms, 123.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToDisplay(tt.value, tt.from, tt.to); got != tt.want {
+				t.Fatalf("ToDisplay(%v, %s, %s) = %v, want %v", tt.value, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
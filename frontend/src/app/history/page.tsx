@@ -23,7 +23,7 @@ export default function HistoryPage() {
 
   const loadHistory = async () => {
     try {
-      const data = await api.get<HistoryResponse>('/history?limit=20&offset=0');
+      const data = await api.get<HistoryResponse>('/history?limit=20');
       setHistory(data);
     } catch (error) {
       console.error('Failed to load history:', error);
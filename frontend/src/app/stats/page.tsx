@@ -122,25 +122,37 @@ export default function StatsPage() {
               <Card key={pr.exercise_name}>
                 <h3 className="font-semibold text-lg mb-2">{pr.exercise_name}</h3>
                 <div className="space-y-1 text-sm">
-                  {pr.max_weight && (
+                  {pr.heaviest_single && (
                     <div className="flex justify-between">
-                      <span className="text-gray-600">Max Weight:</span>
-                      <span className="font-medium">{pr.max_weight} lbs</span>
+                      <span className="text-gray-600">Heaviest Set:</span>
+                      <span className="font-medium">
+                        {pr.heaviest_single.value} lbs x {pr.heaviest_single.reps}
+                      </span>
                     </div>
                   )}
-                  <div className="flex justify-between">
-                    <span className="text-gray-600">Max Reps:</span>
-                    <span className="font-medium">{pr.max_reps}</span>
-                  </div>
-                  {pr.max_volume && (
+                  {pr.best_e1rm && (
                     <div className="flex justify-between">
-                      <span className="text-gray-600">Max Volume:</span>
-                      <span className="font-medium">{pr.max_volume.toFixed(0)} lbs</span>
+                      <span className="text-gray-600">Est. 1RM:</span>
+                      <span className="font-medium">{pr.best_e1rm.value} lbs</span>
+                    </div>
+                  )}
+                  {pr.most_reps && (
+                    <div className="flex justify-between">
+                      <span className="text-gray-600">Max Reps:</span>
+                      <span className="font-medium">{pr.most_reps.value}</span>
+                    </div>
+                  )}
+                  {pr.best_session_volume && (
+                    <div className="flex justify-between">
+                      <span className="text-gray-600">Best Session Volume:</span>
+                      <span className="font-medium">{pr.best_session_volume.value.toFixed(0)} lbs</span>
+                    </div>
+                  )}
+                  {pr.rep_maxes.length > 0 && (
+                    <div className="text-xs text-gray-500 pt-2 border-t mt-2">
+                      {pr.rep_maxes.map((rm) => `${rm.rep_count}RM ${rm.value}`).join(' · ')}
                     </div>
                   )}
-                  <div className="text-xs text-gray-500 pt-2 border-t mt-2">
-                    {new Date(pr.achieved_at).toLocaleDateString()}
-                  </div>
                 </div>
                 <Link
                   to={`/stats/progress/${encodeURIComponent(pr.exercise_name)}`}
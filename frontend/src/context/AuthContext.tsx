@@ -1,6 +1,6 @@
 import { createContext, useContext, useState, useEffect, type ReactNode } from 'react';
 import { api } from '../lib/api';
-import { setToken, setUser, getToken, getUser, clearAuth } from '../lib/auth';
+import { setToken, setRefreshToken, setUser, getToken, getRefreshToken, getUser, clearAuth } from '../lib/auth';
 import type { User, AuthResponse } from '../lib/types';
 
 interface AuthContextType {
@@ -35,6 +35,7 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     });
 
     setToken(response.token);
+    setRefreshToken(response.refresh_token);
     setUser(response.user);
     setUserState(response.user);
   };
@@ -47,11 +48,14 @@ export function AuthProvider({ children }: { children: ReactNode }) {
     });
 
     setToken(response.token);
+    setRefreshToken(response.refresh_token);
     setUser(response.user);
     setUserState(response.user);
   };
 
   const logout = () => {
+    const refreshToken = getRefreshToken();
+    api.post('/auth/logout', refreshToken ? { refresh_token: refreshToken } : undefined).catch(() => {});
     clearAuth();
     setUserState(null);
     window.location.href = '/login';
@@ -1,5 +1,5 @@
-import { getToken, clearAuth } from './auth';
-import type { ApiError } from './types';
+import { getToken, getRefreshToken, setToken, setRefreshToken, clearAuth } from './auth';
+import type { ApiError, TokenPair } from './types';
 
 const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || 'http://localhost:8080/api/v1';
 
@@ -18,9 +18,40 @@ export class APIError extends Error {
 }
 
 class APIClient {
+  private refreshing: Promise<boolean> | null = null;
+
+  private async refreshTokens(): Promise<boolean> {
+    const refreshToken = getRefreshToken();
+    if (!refreshToken) return false;
+
+    // Share one in-flight refresh between concurrent requests; a second
+    // rotation of the same token would be treated as reuse by the server.
+    if (!this.refreshing) {
+      this.refreshing = fetch(`${API_BASE_URL}/auth/refresh`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ refresh_token: refreshToken }),
+      })
+        .then(async (response) => {
+          if (!response.ok) return false;
+          const tokens: TokenPair = await response.json();
+          setToken(tokens.token);
+          setRefreshToken(tokens.refresh_token);
+          return true;
+        })
+        .catch(() => false)
+        .finally(() => {
+          this.refreshing = null;
+        });
+    }
+
+    return this.refreshing;
+  }
+
   private async request<T>(
     endpoint: string,
-    options: RequestInit = {}
+    options: RequestInit = {},
+    retry = true
   ): Promise<T> {
     const token = getToken();
 
@@ -45,6 +76,9 @@ class APIClient {
       });
 
       if (response.status === 401) {
+        if (retry && (await this.refreshTokens())) {
+          return this.request<T>(endpoint, options, false);
+        }
         clearAuth();
         window.location.href = '/login';
         throw new Error('Unauthorized');
@@ -1,4 +1,5 @@
 const TOKEN_KEY = 'cadence_token';
+const REFRESH_TOKEN_KEY = 'cadence_refresh_token';
 const USER_KEY = 'cadence_user';
 
 export const setToken = (token: string): void => {
@@ -13,6 +14,18 @@ export const clearToken = (): void => {
   localStorage.removeItem(TOKEN_KEY);
 };
 
+export const setRefreshToken = (token: string): void => {
+  localStorage.setItem(REFRESH_TOKEN_KEY, token);
+};
+
+export const getRefreshToken = (): string | null => {
+  return localStorage.getItem(REFRESH_TOKEN_KEY);
+};
+
+export const clearRefreshToken = (): void => {
+  localStorage.removeItem(REFRESH_TOKEN_KEY);
+};
+
 export const setUser = (user: unknown): void => {
   localStorage.setItem(USER_KEY, JSON.stringify(user));
 };
@@ -33,6 +46,7 @@ export const clearUser = (): void => {
 
 export const clearAuth = (): void => {
   clearToken();
+  clearRefreshToken();
   clearUser();
 };
 
@@ -1,7 +1,11 @@
+export type WeightUnit = 'kg' | 'lb';
+
 export interface User {
   id: number;
   email: string;
   username: string;
+  preferred_unit: WeightUnit;
+  timezone: string;
   created_at: string;
   updated_at: string;
 }
@@ -9,25 +13,46 @@ export interface User {
 export interface AuthResponse {
   user: User;
   token: string;
+  refresh_token: string;
+  expires_in: number;
+}
+
+export interface TokenPair {
+  token: string;
+  refresh_token: string;
+  expires_in: number;
 }
 
 export interface WorkoutSession {
   id: number;
   user_id: number;
   name: string;
-  status: 'active' | 'completed';
+  status: 'active' | 'paused' | 'completed' | 'abandoned';
   started_at: string;
   completed_at?: string;
+  abandoned_at?: string;
+  active_seconds: number;
+  notes?: string;
+  version: number;
   created_at: string;
   updated_at: string;
+  pauses?: WorkoutPause[];
   exercises?: Exercise[];
 }
 
+export interface WorkoutPause {
+  id: number;
+  paused_at: string;
+  resumed_at?: string;
+}
+
 export interface Exercise {
   id: number;
   workout_session_id: number;
   name: string;
   order_index: number;
+  notes?: string;
+  version: number;
   created_at: string;
   updated_at: string;
   sets?: Set[];
@@ -39,7 +64,9 @@ export interface Set {
   set_number: number;
   reps: number;
   weight?: number;
+  weight_unit: WeightUnit;
   is_bodyweight: boolean;
+  notes?: string;
   created_at: string;
   updated_at: string;
 }
@@ -47,7 +74,9 @@ export interface Set {
 export interface SetInput {
   reps: number;
   weight?: number;
+  weight_unit?: WeightUnit;
   is_bodyweight: boolean;
+  notes?: string;
 }
 
 export interface CreateWorkoutRequest {
@@ -56,26 +85,46 @@ export interface CreateWorkoutRequest {
 
 export interface CreateExerciseRequest {
   name: string;
+  notes?: string;
   sets: SetInput[];
 }
 
 export interface UpdateExerciseRequest {
   name?: string;
+  notes?: string;
   sets?: SetInput[];
 }
 
+export type E1RMFormula = 'epley' | 'brzycki' | 'lombardi';
+
+export interface PRRecord {
+  category: 'heaviest_single' | 'rep_max' | 'e1rm' | 'most_reps' | 'session_volume';
+  rep_count?: number;
+  value: number;
+  set_id?: number;
+  reps?: number;
+  weight?: number;
+  workout_id: number;
+  workout_name: string;
+  achieved_at: string;
+}
+
 export interface PersonalRecord {
+  exercise_definition_id: number;
   exercise_name: string;
-  max_weight?: number;
-  max_reps: number;
-  max_volume?: number;
-  achieved_at: string;
+  formula: E1RMFormula;
+  heaviest_single?: PRRecord;
+  best_e1rm?: PRRecord;
+  most_reps?: PRRecord;
+  best_session_volume?: PRRecord;
+  rep_maxes: PRRecord[];
 }
 
 export interface WorkoutSummary {
   id: number;
   name: string;
   completed_at: string;
+  notes?: string;
   exercise_count: number;
   total_sets: number;
   total_volume: number;
@@ -90,6 +139,7 @@ export interface WorkoutInWeek {
 
 export interface WeeklySummary {
   week: string;
+  unit: WeightUnit;
   total_workouts: number;
   total_exercises: number;
   total_volume: number;
@@ -101,16 +151,21 @@ export interface ProgressDataPoint {
   max_weight?: number;
   max_reps: number;
   volume: number;
+  e1rm?: number;
+  notes?: string[];
 }
 
 export interface ProgressResponse {
   exercise_name: string;
+  formula: E1RMFormula;
+  unit: WeightUnit;
   data_points: ProgressDataPoint[];
 }
 
 export interface HistoryResponse {
   workouts: WorkoutSummary[];
-  total: number;
+  next_cursor: string | null;
+  unit: WeightUnit;
 }
 
 export interface PRsResponse {
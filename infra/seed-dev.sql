@@ -1,63 +1,6 @@
--- Cadence Workout Logger - Database Schema
--- This script initializes the PostgreSQL database with all required tables and indexes
-
--- Users table
-CREATE TABLE IF NOT EXISTS users (
-    id SERIAL PRIMARY KEY,
-    email VARCHAR(255) UNIQUE NOT NULL,
-    password_hash VARCHAR(255) NOT NULL,
-    username VARCHAR(100) NOT NULL,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-);
-
-CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
-
--- Workout sessions table
-CREATE TABLE IF NOT EXISTS workout_sessions (
-    id SERIAL PRIMARY KEY,
-    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-    name VARCHAR(255),
-    status VARCHAR(20) NOT NULL DEFAULT 'active',
-    started_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    completed_at TIMESTAMP WITH TIME ZONE,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    CONSTRAINT chk_status CHECK (status IN ('active', 'completed'))
-);
-
-CREATE INDEX IF NOT EXISTS idx_workout_sessions_user_id ON workout_sessions(user_id);
-CREATE INDEX IF NOT EXISTS idx_workout_sessions_status ON workout_sessions(status);
-CREATE INDEX IF NOT EXISTS idx_workout_sessions_completed_at ON workout_sessions(completed_at) WHERE completed_at IS NOT NULL;
-
--- Exercises table (individual exercises within a workout)
-CREATE TABLE IF NOT EXISTS exercises (
-    id SERIAL PRIMARY KEY,
-    workout_session_id INTEGER NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
-    name VARCHAR(255) NOT NULL,
-    order_index INTEGER NOT NULL DEFAULT 0,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
-);
-
-CREATE INDEX IF NOT EXISTS idx_exercises_workout_session_id ON exercises(workout_session_id);
-CREATE INDEX IF NOT EXISTS idx_exercises_name ON exercises(name);
-
--- Sets table (individual sets within an exercise)
-CREATE TABLE IF NOT EXISTS sets (
-    id SERIAL PRIMARY KEY,
-    exercise_id INTEGER NOT NULL REFERENCES exercises(id) ON DELETE CASCADE,
-    set_number INTEGER NOT NULL,
-    reps INTEGER NOT NULL,
-    weight DECIMAL(10, 2),
-    is_bodyweight BOOLEAN NOT NULL DEFAULT false,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    CONSTRAINT chk_reps CHECK (reps > 0),
-    CONSTRAINT chk_weight CHECK (weight IS NULL OR weight >= 0)
-);
-
-CREATE INDEX IF NOT EXISTS idx_sets_exercise_id ON sets(exercise_id);
+-- Cadence Workout Logger - Development seed data
+-- Schema is managed by the backend migrations; apply them first with:
+--   cd backend && go run ./cmd/cadence migrate up
 
 -- Insert seed data for development (password is "password123")
 -- Password hash generated with bcrypt cost 10